@@ -38,17 +38,34 @@ type Bot struct {
 	PK      *pkgo.Session
 	Metrics *metrics.Client
 
+	// PluralKitBots are all PluralKit-compatible bots. PK is the first bot's API session.
+	PluralKitBots []PluralKitBot
+
 	user   discord.User
 	Config Config
 
 	Cabinet store.Cabinet
 
-	queues   map[discord.WebhookID]*queue
+	queues   map[discord.ChannelID]*queue
 	queuesMu sync.Mutex
+	// queuesReplayed makes sure queues left over from before a restart are only flushed once
+	queuesReplayed sync.Once
+	// healthChecks makes sure only one daily delivery health check is running
+	healthChecks sync.Once
+	// linkChecks makes sure only one log link permission check is running
+	linkChecks sync.Once
+	// deliveryFlushes makes sure delivery counts are only written to the database by one loop
+	deliveryFlushes sync.Once
+	deliveries      deliveryCounts
+
+	dossierLocks   map[dossierKey]*dossierLock
+	dossierLocksMu sync.Mutex
 
 	webhookClients   map[discord.WebhookID]*webhook.Client
 	webhookClientsMu sync.Mutex
 
+	rateLimits rateLimits
+
 	users   map[discord.UserID]*discord.User
 	usersMu sync.Mutex
 }
@@ -81,9 +98,9 @@ func New(c Config) (*Bot, error) {
 	bot := &Bot{
 		Config: c,
 		Router: bcr.NewFromShardManager("Bot "+c.Auth.Discord, mgr),
-		PK:     pkgo.New(""),
 
-		queues:         map[discord.WebhookID]*queue{},
+		queues:         map[discord.ChannelID]*queue{},
+		dossierLocks:   map[dossierKey]*dossierLock{},
 		webhookClients: map[discord.WebhookID]*webhook.Client{},
 		users:          map[discord.UserID]*discord.User{},
 	}
@@ -124,14 +141,20 @@ func New(c Config) (*Bot, error) {
 	if bot.Config.Auth.Influx.URL != "" {
 		c := bot.Config.Auth.Influx
 		bot.Metrics = metrics.New(c.URL, c.Token, c.Organization, c.Database)
+		bot.Metrics.QueueDepth = bot.queueDepth
 	}
 
 	// add interaction handler
 	bot.AddHandler(bot.interactionCreate)
 
-	// set up pkgo user agent
+	// set up PluralKit-compatible bots, with the pkgo user agent
 	// TODO: get version from git tags after full release
-	bot.PK.UserAgent = fmt.Sprintf("Catalogger/v2 (+https://github.com/starshine-sys/catalogger; %v)", bot.Config.Bot.Owner)
+	bot.PluralKitBots, err = newPluralKitBots(c.PluralKit,
+		fmt.Sprintf("Catalogger/v2 (+https://github.com/starshine-sys/catalogger; %v)", bot.Config.Bot.Owner))
+	if err != nil {
+		return nil, errors.Wrap(err, "setting up PluralKit-compatible bots")
+	}
+	bot.PK = bot.PluralKitBots[0].API
 
 	return bot, nil
 }
@@ -169,4 +192,10 @@ func (bot *Bot) ready(ev *gateway.ReadyEvent) {
 		return
 	}
 	bot.user = ev.User
+
+	// the bot user is needed to send embeds, so pending queues can only be flushed now
+	bot.queuesReplayed.Do(func() { go bot.FlushQueues() })
+	bot.healthChecks.Do(func() { go bot.checkDeliveryHealth() })
+	bot.linkChecks.Do(func() { go bot.checkLogLinks() })
+	bot.deliveryFlushes.Do(func() { go bot.flushDeliveries() })
 }
@@ -6,6 +6,7 @@ import (
 	"emperror.dev/errors"
 	"github.com/BurntSushi/toml"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/db"
 )
 
 type Config struct {
@@ -13,6 +14,11 @@ type Config struct {
 	Bot       BotConfig       `toml:"bot"`
 	Dashboard DashboardConfig `toml:"dashboard"`
 	Info      InfoConfig      `toml:"info"`
+	Queue     QueueConfig     `toml:"queue"`
+
+	// PluralKit is the list of PluralKit-compatible bots.
+	// If this is empty, only PluralKit itself is used.
+	PluralKit []PluralKitConfig `toml:"pluralkit"`
 }
 
 type AuthConfig struct {
@@ -59,6 +65,27 @@ type DashboardConfig struct {
 	AnnouncementChannel discord.ChannelID `toml:"announcement_channel"`
 }
 
+type PluralKitConfig struct {
+	// UserID is the bot's user ID. This must be the same as its application ID.
+	UserID discord.UserID `toml:"user_id"`
+	// LogFormat is a regular expression matching the embed footer of the bot's log messages.
+	// It must have named groups for "system", "member", "sender", "message", and "original".
+	// If empty, PluralKit's log format is used.
+	LogFormat string `toml:"log_format"`
+	// APIBase is the base URL of the bot's API, including the version.
+	// If empty, PluralKit's API is used.
+	APIBase string `toml:"api_base"`
+}
+
+// QueueConfig is how events are batched before being sent to log channels.
+// Guilds can override these with /config batching.
+type QueueConfig struct {
+	// Default is the policy for all events. Any fields that aren't set use the built-in defaults.
+	Default db.QueuePolicy `toml:"default"`
+	// Events are per-event policies, keyed by event key (for example, "MESSAGE_DELETE").
+	Events map[string]db.QueuePolicy `toml:"events"`
+}
+
 type InfoConfig struct {
 	SupportServer string `toml:"support_server"`
 	DashboardBase string `toml:"dashboard_base"`
@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/webhook"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// deliveryAttempts is how many times a log message is sent before giving up.
+const deliveryAttempts = 3
+
+// Discord JSON error codes that change how a failed delivery is handled.
+const (
+	errCodeUnknownWebhook     httputil.ErrorCode = 10015
+	errCodeMaxWebhooks        httputil.ErrorCode = 30007
+	errCodeMissingAccess      httputil.ErrorCode = 50001
+	errCodeMissingPermissions httputil.ErrorCode = 50013
+)
+
+// failureKind is how a failed delivery should be handled.
+type failureKind int
+
+const (
+	// failureTransient errors (server errors, network errors) are retried after a short delay.
+	failureTransient failureKind = iota
+	// failureUnknownWebhook means the webhook was deleted; the cached webhook is invalidated and a new one is created.
+	failureUnknownWebhook
+	// failureNoWebhook means no webhook can be created in the channel, so the log is sent as a normal bot message instead.
+	failureNoWebhook
+	// failurePermanent errors won't be fixed by retrying, such as the channel being deleted.
+	failurePermanent
+)
+
+// permanentError is a delivery error that won't be fixed by retrying, whatever its error code.
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// classifyFailure returns how the given delivery error should be handled.
+func classifyFailure(err error) failureKind {
+	var permErr permanentError
+	if errors.As(err, &permErr) {
+		return failurePermanent
+	}
+
+	var httpErr *httputil.HTTPError
+	if !errors.As(err, &httpErr) {
+		return failureTransient
+	}
+
+	switch {
+	case httpErr.Code == errCodeUnknownWebhook:
+		return failureUnknownWebhook
+	case httpErr.Code == errCodeMaxWebhooks, httpErr.Code == errCodeMissingPermissions:
+		return failureNoWebhook
+	case httpErr.Code == errCodeMissingAccess:
+		return failurePermanent
+	case httpErr.Status == 429 || httpErr.Status >= 500:
+		return failureTransient
+	}
+	return failurePermanent
+}
+
+// deliver sends a log message to the given channel, through its webhook if possible.
+// Transient errors are retried, deleted webhooks are recreated, and if no webhook can be used at all,
+// the message is sent as a normal bot message instead. files may be nil.
+func (bot *Bot) deliver(channelID discord.ChannelID, data webhook.ExecuteData, files func() []sendpart.File) error {
+	return bot.deliverWith(channelID, data, files, true)
+}
+
+// deliverQueued is like deliver, but returns transient errors immediately instead of waiting to retry them.
+// Only one flush per queue runs at a time, so queues retry later with their timer instead of holding up the next flush.
+func (bot *Bot) deliverQueued(channelID discord.ChannelID, data webhook.ExecuteData, files func() []sendpart.File) error {
+	return bot.deliverWith(channelID, data, files, false)
+}
+
+// deliverWith is deliver, but transient errors are only retried if retryTransient is true.
+func (bot *Bot) deliverWith(channelID discord.ChannelID, data webhook.ExecuteData, files func() []sendpart.File, retryTransient bool) (err error) {
+	var wait time.Duration
+	for attempt := 1; attempt <= deliveryAttempts; attempt++ {
+		time.Sleep(wait)
+		wait = 0
+
+		if files != nil {
+			data.Files = files()
+		}
+
+		var wh *discord.Webhook
+		wh, data.ThreadID, err = bot.getWebhook(channelID)
+		if err == nil {
+			_, err = bot.webhookClient(wh).ExecuteAndWait(data)
+			if err == nil {
+				return nil
+			}
+		}
+
+		switch classifyFailure(err) {
+		case failureTransient:
+			if !retryTransient {
+				return errors.Wrap(err, "delivering log message")
+			}
+			log.Debugf("transient error delivering to %v (attempt %v/%v): %v", channelID, attempt, deliveryAttempts, err)
+			wait = time.Duration(attempt) * time.Second
+		case failureUnknownWebhook:
+			log.Debugf("webhook for %v was deleted, invalidating it", channelID)
+			bot.invalidateWebhook(channelID, wh)
+		case failureNoWebhook:
+			log.Debugf("can't use a webhook in %v, sending as a bot message: %v", channelID, err)
+			return bot.deliverAsBot(channelID, data, files)
+		case failurePermanent:
+			return errors.Wrap(err, "delivering log message")
+		}
+	}
+	return errors.Wrapf(err, "delivering log message after %v attempts", deliveryAttempts)
+}
+
+// deliverAsBot sends a log message as a normal bot message, for channels where webhooks can't be used.
+func (bot *Bot) deliverAsBot(channelID discord.ChannelID, data webhook.ExecuteData, files func() []sendpart.File) error {
+	msg := api.SendMessageData{
+		Content:         data.Content,
+		Embeds:          data.Embeds,
+		AllowedMentions: data.AllowedMentions,
+	}
+	if msg.AllowedMentions == nil {
+		msg.AllowedMentions = &api.AllowedMentions{Parse: []api.AllowedMentionType{}}
+	}
+	if files != nil {
+		msg.Files = files()
+	}
+
+	_, err := bot.Router.Rest.SendMessageComplex(channelID, msg)
+	if err != nil {
+		return botMessageError(err)
+	}
+	return nil
+}
+
+// botMessageError wraps an error from sending a log as a bot message.
+// Bot messages are the last fallback, so client errors (other than rate limits) are permanent:
+// otherwise, missing permissions would be classified as failureNoWebhook again, and retried forever.
+func botMessageError(err error) error {
+	err = errors.Wrap(err, "sending bot message")
+
+	var httpErr *httputil.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status >= 400 && httpErr.Status < 500 && httpErr.Status != 429 {
+		return permanentError{err}
+	}
+	return err
+}
+
+// invalidateWebhook removes a deleted webhook from the cache, so that a new one is created for the channel.
+func (bot *Bot) invalidateWebhook(channelID discord.ChannelID, wh *discord.Webhook) {
+	err := bot.DB.Redis.Do(context.Background(), radix.Cmd(nil, "DEL", webhookKey(channelID)))
+	if err != nil {
+		log.Errorf("deleting cached webhook for %v: %v", channelID, err)
+	}
+
+	if wh == nil {
+		return
+	}
+
+	bot.webhookClientsMu.Lock()
+	delete(bot.webhookClients, wh.ID)
+	bot.webhookClientsMu.Unlock()
+}
+
+// isPermanentFailure returns true if retrying the delivery later won't help.
+func isPermanentFailure(err error) bool {
+	return classifyFailure(err) == failurePermanent
+}
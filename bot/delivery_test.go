@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want failureKind
+	}{
+		{"network error", errors.New("connection reset by peer"), failureTransient},
+		{"rate limited", &httputil.HTTPError{Status: 429}, failureTransient},
+		{"server error", &httputil.HTTPError{Status: 502}, failureTransient},
+		{"unknown webhook", &httputil.HTTPError{Status: 404, Code: errCodeUnknownWebhook}, failureUnknownWebhook},
+		{"wrapped unknown webhook", errors.Wrap(&httputil.HTTPError{Status: 404, Code: errCodeUnknownWebhook}, "executing webhook"), failureUnknownWebhook},
+		{"too many webhooks", &httputil.HTTPError{Status: 400, Code: errCodeMaxWebhooks}, failureNoWebhook},
+		{"missing permissions", &httputil.HTTPError{Status: 403, Code: errCodeMissingPermissions}, failureNoWebhook},
+		{"missing access", &httputil.HTTPError{Status: 403, Code: errCodeMissingAccess}, failurePermanent},
+		{"unknown channel", &httputil.HTTPError{Status: 404, Code: errCodeUnknownChannel}, failurePermanent},
+		{"bad request", &httputil.HTTPError{Status: 400}, failurePermanent},
+		{"webhook missing permissions, then bot message missing permissions", botMessageError(&httputil.HTTPError{Status: 403, Code: errCodeMissingPermissions}), failurePermanent},
+		{"bot message missing access", botMessageError(&httputil.HTTPError{Status: 403, Code: errCodeMissingAccess}), failurePermanent},
+		{"bot message too many webhooks", botMessageError(&httputil.HTTPError{Status: 400, Code: errCodeMaxWebhooks}), failurePermanent},
+		{"bot message rate limited", botMessageError(&httputil.HTTPError{Status: 429}), failureTransient},
+		{"bot message server error", botMessageError(&httputil.HTTPError{Status: 502}), failureTransient},
+		{"bot message network error", botMessageError(errors.New("connection reset by peer")), failureTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFailure(tt.err); got != tt.want {
+				t.Errorf("classifyFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package bot
+
+import (
+	"fmt"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/jackc/pgx/v5"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// dossierEvents are the events that are mirrored into the dossier of the member they concern.
+var dossierEvents = map[string]bool{
+	"GuildMemberAddEvent":          true,
+	"GuildMemberRemoveEvent":       true,
+	"GuildMemberUpdateEvent":       true,
+	"GuildKeyRoleUpdateEvent":      true,
+	"GuildMemberNickUpdateEvent":   true,
+	"GuildMemberAvatarUpdateEvent": true,
+	"GuildMemberKickEvent":         true,
+	"GuildBanAddEvent":             true,
+	"GuildBanRemoveEvent":          true,
+	"MessageDeleteEvent":           true,
+}
+
+// errCodeUnknownChannel is returned by Discord when a channel, such as a dossier thread, has been deleted.
+const errCodeUnknownChannel httputil.ErrorCode = 10003
+
+type dossierKey struct {
+	guildID discord.GuildID
+	userID  discord.UserID
+}
+
+// dossierLock stops two events for the same member from creating two dossier threads,
+// and keeps each member's dossier events in order.
+type dossierLock struct {
+	mu sync.Mutex
+	// users is how many goroutines are holding or waiting for the lock
+	users int
+}
+
+// lockDossier locks the member's dossier, and returns a function that unlocks it.
+func (bot *Bot) lockDossier(guildID discord.GuildID, userID discord.UserID) (unlock func()) {
+	k := dossierKey{guildID, userID}
+
+	bot.dossierLocksMu.Lock()
+	l, ok := bot.dossierLocks[k]
+	if !ok {
+		l = &dossierLock{}
+		bot.dossierLocks[k] = l
+	}
+	l.users++
+	bot.dossierLocksMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		bot.dossierLocksMu.Lock()
+		l.users--
+		if l.users == 0 {
+			delete(bot.dossierLocks, k)
+		}
+		bot.dossierLocksMu.Unlock()
+	}
+}
+
+// SendDossier mirrors embeds into the member's dossier thread, creating it if it doesn't exist yet.
+// If the thread was deleted, a new one is created.
+// This does nothing if the guild doesn't have a dossier channel.
+func (bot *Bot) SendDossier(guildID discord.GuildID, userID discord.UserID, embeds []discord.Embed) {
+	if !guildID.IsValid() || !userID.IsValid() || len(embeds) == 0 {
+		return
+	}
+
+	unlock := bot.lockDossier(guildID, userID)
+	defer unlock()
+
+	ev := logEvent{
+		GuildID: guildID,
+		Name:    "DossierEvent",
+		Policy:  bot.queuePolicy(guildID, "DossierEvent"),
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		threadID, err := bot.dossierThread(guildID, userID)
+		if err != nil {
+			log.Errorf("getting dossier thread for %v in guild %v: %v", userID, guildID, err)
+			return
+		}
+		if !threadID.IsValid() {
+			return
+		}
+
+		err = bot.sendTo(threadID, ev, embeds, nil)
+		if !isUnknownChannel(err) {
+			return
+		}
+
+		log.Debugf("dossier thread %v for %v in guild %v was deleted, recreating it", threadID, userID, guildID)
+		if err := bot.DB.DeleteDossierThread(guildID, userID); err != nil {
+			log.Errorf("removing dossier thread for %v in guild %v: %v", userID, guildID, err)
+			return
+		}
+	}
+}
+
+// forgetDossierThread forgets the dossier thread with the given ID if it was deleted,
+// so the member's next dossier event creates a new one. This is used when queued dossier events fail to send.
+func (bot *Bot) forgetDossierThread(channelID discord.ChannelID, err error) {
+	if !isUnknownChannel(err) {
+		return
+	}
+
+	if err := bot.DB.DeleteDossierThreadByID(channelID); err != nil {
+		log.Errorf("removing dossier thread %v: %v", channelID, err)
+	}
+}
+
+// isUnknownChannel returns true if err means the channel it was sent to doesn't exist.
+func isUnknownChannel(err error) bool {
+	var httpErr *httputil.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == errCodeUnknownChannel
+}
+
+// HasDossiers returns true if the guild has a dossier channel.
+func (bot *Bot) HasDossiers(guildID discord.GuildID) bool {
+	settings, err := bot.guildSettings(guildID)
+	if err != nil {
+		log.Errorf("getting dossier channel for guild %v: %v", guildID, err)
+		return false
+	}
+	return settings.DossierChannel.IsValid()
+}
+
+// dossierThread returns the member's dossier thread, creating it if needed.
+// If the guild doesn't have a dossier channel, the returned ID is not valid.
+// The caller must hold the member's dossier lock.
+func (bot *Bot) dossierThread(guildID discord.GuildID, userID discord.UserID) (discord.ChannelID, error) {
+	settings, err := bot.guildSettings(guildID)
+	if err != nil {
+		return discord.NullChannelID, errors.Wrap(err, "getting dossier channel")
+	}
+	forumID := settings.DossierChannel
+	if !forumID.IsValid() {
+		return discord.NullChannelID, nil
+	}
+
+	threadID, err := bot.DB.DossierThread(guildID, userID)
+	if err == nil {
+		return threadID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return discord.NullChannelID, errors.Wrap(err, "getting dossier thread")
+	}
+
+	name := userID.String()
+	starter := discord.Embed{
+		Title:       "Dossier",
+		Description: fmt.Sprintf("Events concerning %v will be logged in this post.", userID.Mention()),
+		Color:       common.ColourPurple,
+		Footer:      &discord.EmbedFooter{Text: "User ID: " + userID.String()},
+		Timestamp:   discord.NowTimestamp(),
+	}
+	if u, err := bot.GuildUser(guildID, userID); err == nil {
+		name = fmt.Sprintf("%v (%v)", u.Tag(), userID)
+		starter.Thumbnail = &discord.EmbedThumbnail{URL: u.AvatarURL()}
+	}
+
+	// arikawa doesn't support creating forum posts, so the request has to be made manually
+	var thread discord.Channel
+	err = bot.Router.Rest.RequestJSON(
+		&thread, "POST",
+		api.EndpointChannels+forumID.String()+"/threads",
+		httputil.WithJSONBody(forumPostData{
+			Name:    name,
+			Message: forumPostMessage{Embeds: []discord.Embed{starter}},
+		}),
+	)
+	if err != nil {
+		return discord.NullChannelID, errors.Wrap(err, "creating forum post")
+	}
+
+	err = bot.DB.SetDossierThread(guildID, userID, thread.ID)
+	if err != nil {
+		return discord.NullChannelID, errors.Wrap(err, "storing dossier thread")
+	}
+	return thread.ID, nil
+}
+
+type forumPostData struct {
+	Name    string           `json:"name"`
+	Message forumPostMessage `json:"message"`
+}
+
+type forumPostMessage struct {
+	Embeds []discord.Embed `json:"embeds"`
+}
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v5"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// guildSettingsTTL is how long a guild's settings are cached for.
+// Config commands clear the cache when they change a setting, so this only matters if the database is changed directly.
+const guildSettingsTTL = 10 * time.Minute
+
+// guildSettings are the settings needed to route and send every event in a guild.
+// They're cached in Redis, so they aren't fetched from the database for every event.
+type guildSettings struct {
+	QueuePolicies  db.QueuePolicies  `json:"queue_policies,omitempty"`
+	AlertRoles     db.AlertRoles     `json:"alert_roles,omitempty"`
+	IgnoreRules    []db.IgnoreRule   `json:"ignore_rules,omitempty"`
+	LogLink        discord.ChannelID `json:"log_link,omitempty"`
+	DossierChannel discord.ChannelID `json:"dossier_channel,omitempty"`
+}
+
+func guildSettingsKey(id discord.GuildID) string {
+	return "guild-settings:" + id.String()
+}
+
+// guildSettings returns the guild's settings, from the cache if possible.
+func (bot *Bot) guildSettings(guildID discord.GuildID) (s guildSettings, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	key := guildSettingsKey(guildID)
+
+	var raw []byte
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(&raw, "GET", key))
+	if err != nil {
+		log.Errorf("getting cached settings for guild %v: %v", guildID, err)
+	} else if raw != nil {
+		err = json.Unmarshal(raw, &s)
+		if err == nil {
+			return s, nil
+		}
+		log.Errorf("unmarshaling cached settings for guild %v: %v", guildID, err)
+		s = guildSettings{}
+	}
+
+	s.QueuePolicies, err = bot.DB.QueuePolicies(guildID)
+	if err != nil {
+		return s, errors.Wrap(err, "getting batching policies")
+	}
+	s.AlertRoles, err = bot.DB.AlertRoles(guildID)
+	if err != nil {
+		return s, errors.Wrap(err, "getting alert roles")
+	}
+	s.IgnoreRules, err = bot.DB.IgnoreRules(guildID)
+	if err != nil {
+		return s, errors.Wrap(err, "getting ignore rules")
+	}
+	s.DossierChannel, err = bot.DB.DossierChannel(guildID)
+	if err != nil {
+		return s, errors.Wrap(err, "getting dossier channel")
+	}
+
+	link, err := bot.DB.LogLink(guildID)
+	if err == nil {
+		s.LogLink = link.ChannelID
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return s, errors.Wrap(err, "getting log link")
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		log.Errorf("marshaling settings for guild %v: %v", guildID, err)
+		return s, nil
+	}
+
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(nil, "SET", key, string(b), "EX", strconv.Itoa(int(guildSettingsTTL.Seconds()))))
+	if err != nil {
+		log.Errorf("caching settings for guild %v: %v", guildID, err)
+	}
+	return s, nil
+}
+
+// InvalidateGuildSettings clears the guild's cached settings.
+// This must be called after changing batching policies, alert roles, ignore rules, log links, or the dossier channel.
+func (bot *Bot) InvalidateGuildSettings(guildID discord.GuildID) {
+	err := bot.DB.Redis.Do(context.Background(), radix.Cmd(nil, "DEL", guildSettingsKey(guildID)))
+	if err != nil {
+		log.Errorf("clearing cached settings for guild %v: %v", guildID, err)
+	}
+}
@@ -0,0 +1,267 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/webhook"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+const (
+	// healthCheckInterval is how often guilds are alerted about log channels that are failing.
+	healthCheckInterval = 24 * time.Hour
+	// healthCheckPoll is how often the bot checks whether a health check is due.
+	// The time of the last check is stored in Redis, so restarts don't delay or skip checks.
+	healthCheckPoll = time.Hour
+	// healthCheckKey is the Redis key holding the Unix time of the last health check.
+	healthCheckKey = "health:last-check"
+	// healthMinFailures is how many deliveries in a row have to fail before the guild is alerted.
+	healthMinFailures = 3
+	// healthMaxLines is the maximum number of failing destinations listed in an alert.
+	healthMaxLines = 20
+
+	// deliveryFlushInterval is how often delivery counts are written to the database.
+	deliveryFlushInterval = time.Minute
+)
+
+type deliveryKey struct {
+	guildID   discord.GuildID
+	channelID discord.ChannelID
+	event     string
+}
+
+// deliveryCounts collects delivery counts in memory, so the database isn't written to for every log message.
+type deliveryCounts struct {
+	mu     sync.Mutex
+	counts map[deliveryKey]*db.DeliveryCounts
+}
+
+// recordDelivery records the final outcome of n deliveries of an event to a log channel. If err is nil, they succeeded.
+// Failures that are going to be retried shouldn't be recorded.
+func (bot *Bot) recordDelivery(guildID discord.GuildID, channelID discord.ChannelID, eventName string, n int, err error) {
+	if !guildID.IsValid() || n == 0 {
+		return
+	}
+
+	// events are stored by key where possible, as that's what's shown to users
+	event := eventName
+	if key, ok := common.EventKeys[eventName]; ok {
+		event = key
+	}
+
+	bot.deliveries.mu.Lock()
+	defer bot.deliveries.mu.Unlock()
+
+	if bot.deliveries.counts == nil {
+		bot.deliveries.counts = map[deliveryKey]*db.DeliveryCounts{}
+	}
+
+	k := deliveryKey{guildID, channelID, event}
+	c, ok := bot.deliveries.counts[k]
+	if !ok {
+		c = &db.DeliveryCounts{}
+		bot.deliveries.counts[k] = c
+	}
+	c.Add(n, err)
+}
+
+// recordBatch records the delivery of a batch of queued embeds to a log channel.
+func (bot *Bot) recordBatch(channelID discord.ChannelID, batch []queuedEmbed, err error) {
+	type key struct {
+		guildID discord.GuildID
+		event   string
+	}
+
+	counts := map[key]int{}
+	for _, e := range batch {
+		counts[key{e.GuildID, e.Event}]++
+	}
+
+	for k, n := range counts {
+		bot.recordDelivery(k.guildID, channelID, k.event, n, err)
+	}
+}
+
+// flushDeliveries runs FlushDeliveries every minute. It never returns.
+func (bot *Bot) flushDeliveries() {
+	ticker := time.NewTicker(deliveryFlushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		bot.FlushDeliveries()
+	}
+}
+
+// FlushDeliveries writes the delivery counts collected since the last flush to the database.
+func (bot *Bot) FlushDeliveries() {
+	bot.deliveries.mu.Lock()
+	counts := bot.deliveries.counts
+	bot.deliveries.counts = nil
+	bot.deliveries.mu.Unlock()
+
+	for k, c := range counts {
+		err := bot.DB.RecordDeliveries(k.guildID, k.channelID, k.event, *c)
+		if err != nil {
+			log.Errorf("recording deliveries of %v to %v in guild %v: %v", k.event, k.channelID, k.guildID, err)
+		}
+	}
+}
+
+// checkDeliveryHealth runs CheckDeliveryHealth once a day. It never returns.
+func (bot *Bot) checkDeliveryHealth() {
+	ticker := time.NewTicker(healthCheckPoll)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		if bot.healthCheckDue() {
+			bot.CheckDeliveryHealth()
+		}
+	}
+}
+
+// healthCheckDue returns true if the last health check was at least healthCheckInterval ago, and marks it as done if so.
+// If no check has been done yet, the interval starts now.
+func (bot *Bot) healthCheckDue() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var raw []byte
+	err := bot.DB.Redis.Do(ctx, radix.Cmd(&raw, "GET", healthCheckKey))
+	if err != nil {
+		log.Errorf("getting time of last delivery health check: %v", err)
+		return false
+	}
+
+	// an unparseable time is treated as overdue, so it's overwritten
+	if raw != nil {
+		last, err := strconv.ParseInt(string(raw), 10, 64)
+		if err == nil && time.Since(time.Unix(last, 0)) < healthCheckInterval {
+			return false
+		}
+	}
+
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(nil, "SET", healthCheckKey, strconv.FormatInt(time.Now().Unix(), 10)))
+	if err != nil {
+		log.Errorf("storing time of last delivery health check: %v", err)
+		return false
+	}
+	return raw != nil
+}
+
+// CheckDeliveryHealth alerts every guild with log channels that have been failing in the last day.
+func (bot *Bot) CheckDeliveryHealth() {
+	if !bot.ShouldLog() {
+		return
+	}
+
+	stats, err := bot.DB.FailingDeliveries(time.Now().Add(-healthCheckInterval), healthMinFailures)
+	if err != nil {
+		log.Errorf("getting failing deliveries: %v", err)
+		return
+	}
+
+	var guilds []discord.GuildID
+	byGuild := map[discord.GuildID][]db.DeliveryStat{}
+	for _, s := range stats {
+		if _, ok := byGuild[s.GuildID]; !ok {
+			guilds = append(guilds, s.GuildID)
+		}
+		byGuild[s.GuildID] = append(byGuild[s.GuildID], s)
+	}
+
+	if len(guilds) > 0 {
+		log.Infof("alerting %v guild(s) about failing log channels", len(guilds))
+	}
+
+	for _, guildID := range guilds {
+		bot.alertFailing(guildID, byGuild[guildID])
+	}
+}
+
+// alertFailing tells a guild that some of its log channels are failing.
+// The alert is sent to one of the guild's other log channels, or to the guild owner if none of those work.
+func (bot *Bot) alertFailing(guildID discord.GuildID, stats []db.DeliveryStat) {
+	var (
+		failing []discord.ChannelID
+		lines   []string
+	)
+	for _, s := range stats {
+		if !common.Contains(failing, s.ChannelID) {
+			failing = append(failing, s.ChannelID)
+		}
+
+		reason := "unknown error"
+		if s.LastError != nil {
+			reason = *s.LastError
+		}
+		lines = append(lines, fmt.Sprintf("%v (**%v**): %v failures in a row, last error: `%v`",
+			s.ChannelID.Mention(), common.EventName(s.Event), s.ConsecutiveFailures, reason))
+	}
+	if len(lines) > healthMaxLines {
+		lines = append(lines[:healthMaxLines], fmt.Sprintf("...and %v more", len(lines)-healthMaxLines))
+	}
+
+	e := discord.Embed{
+		Title: "Some logs aren't being delivered",
+		Description: "Logs couldn't be sent to these channels in the last day. " +
+			"Make sure the bot can view them, send messages in them, and manage webhooks in them.\n\n" +
+			strings.Join(lines, "\n"),
+		Color:     common.ColourRed,
+		Footer:    &discord.EmbedFooter{Text: "Use /config status to check which logs are being delivered."},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	lc, err := bot.DB.Channels(guildID)
+	if err != nil {
+		log.Errorf("getting channels for guild %v: %v", guildID, err)
+	} else {
+		var others []discord.ChannelID
+		for _, ev := range common.LogEvents {
+			id := lc.Channels.ForKey(ev.Key)
+			if id.IsValid() && !common.Contains(failing, id) && !common.Contains(others, id) {
+				others = append(others, id)
+			}
+		}
+
+		for _, id := range others {
+			err = bot.deliver(id, webhook.ExecuteData{
+				AvatarURL:       bot.user.AvatarURL(),
+				Embeds:          []discord.Embed{e},
+				AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+			}, nil)
+			if err == nil {
+				return
+			}
+			log.Debugf("sending delivery alert for guild %v to %v: %v", guildID, id, err)
+		}
+	}
+
+	// none of the guild's other log channels work, so tell the owner directly
+	g, err := bot.Cabinet.Guild(context.Background(), guildID)
+	if err != nil {
+		log.Errorf("getting guild %v to alert its owner: %v", guildID, err)
+		return
+	}
+	e.Author = &discord.EmbedAuthor{Name: g.Name, Icon: g.IconURL()}
+
+	ch, err := bot.Router.Rest.CreatePrivateChannel(g.OwnerID)
+	if err != nil {
+		log.Errorf("opening DM with owner of guild %v: %v", guildID, err)
+		return
+	}
+
+	_, err = bot.Router.Rest.SendEmbeds(ch.ID, e)
+	if err != nil {
+		log.Errorf("sending delivery alert to owner of guild %v: %v", guildID, err)
+	}
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// EventSubject is what a logged event is about, used to match per-event ignore rules.
+type EventSubject struct {
+	UserID    discord.UserID
+	ChannelID discord.ChannelID
+	RoleID    discord.RoleID
+
+	// ParentID is the channel's parent, if it's known without looking it up in the cache.
+	// This is only needed for channel create events, as the new channel isn't cached yet.
+	ParentID discord.ChannelID
+}
+
+// merge fills in any unset fields in s with the fields of other.
+func (s EventSubject) merge(other EventSubject) EventSubject {
+	if !s.UserID.IsValid() {
+		s.UserID = other.UserID
+	}
+	if !s.ChannelID.IsValid() {
+		s.ChannelID = other.ChannelID
+	}
+	if !s.RoleID.IsValid() {
+		s.RoleID = other.RoleID
+	}
+	if !s.ParentID.IsValid() {
+		s.ParentID = other.ParentID
+	}
+	return s
+}
+
+// eventSubject returns what the given event is about, as far as can be determined from the event alone.
+func eventSubject(event any) (s EventSubject) {
+	switch ev := event.(type) {
+	case *gateway.GuildMemberAddEvent:
+		s.UserID = ev.User.ID
+	case *gateway.GuildMemberRemoveEvent:
+		s.UserID = ev.User.ID
+	case *gateway.GuildMemberUpdateEvent:
+		s.UserID = ev.User.ID
+	case *gateway.GuildBanAddEvent:
+		s.UserID = ev.User.ID
+	case *gateway.GuildBanRemoveEvent:
+		s.UserID = ev.User.ID
+	case *gateway.ChannelCreateEvent:
+		s.ChannelID = ev.ID
+		s.ParentID = ev.ParentID
+	case *gateway.ChannelUpdateEvent:
+		s.ChannelID = ev.ID
+		s.ParentID = ev.ParentID
+	case *gateway.ChannelDeleteEvent:
+		s.ChannelID = ev.ID
+		s.ParentID = ev.ParentID
+	case *gateway.GuildRoleCreateEvent:
+		s.RoleID = ev.Role.ID
+	case *gateway.GuildRoleUpdateEvent:
+		s.RoleID = ev.Role.ID
+	case *gateway.GuildRoleDeleteEvent:
+		s.RoleID = ev.RoleID
+	case *gateway.InviteCreateEvent:
+		s.ChannelID = ev.ChannelID
+	case *gateway.InviteDeleteEvent:
+		s.ChannelID = ev.ChannelID
+	case *gateway.MessageUpdateEvent:
+		s.ChannelID = ev.ChannelID
+		s.UserID = ev.Author.ID
+	case *gateway.MessageDeleteEvent:
+		s.ChannelID = ev.ChannelID
+	case *gateway.MessageDeleteBulkEvent:
+		s.ChannelID = ev.ChannelID
+	}
+	return s
+}
+
+// matchIgnoreRule returns the first of the guild's ignore rules for this event that matches the subject.
+// channels should be the subject's channel ancestry, as returned by channelAncestry.
+func (bot *Bot) matchIgnoreRule(
+	ctx context.Context,
+	guildID discord.GuildID, eventName string,
+	s EventSubject, channels []discord.ChannelID,
+) (rule db.IgnoreRule, ok bool) {
+	if !guildID.IsValid() {
+		return rule, false
+	}
+
+	key, ok := common.EventKeys[eventName]
+	if !ok {
+		return rule, false
+	}
+
+	settings, err := bot.guildSettings(guildID)
+	if err != nil {
+		log.Errorf("getting ignore rules for %v in guild %v: %v", key, guildID, err)
+		return rule, false
+	}
+
+	// the member's roles are only looked up if a rule needs them
+	var (
+		roles        []discord.RoleID
+		rolesFetched bool
+	)
+
+	memberRoles := func() []discord.RoleID {
+		if !rolesFetched && s.UserID.IsValid() {
+			rolesFetched = true
+			m, err := bot.Cabinet.Member(ctx, guildID, s.UserID)
+			if err == nil {
+				roles = m.RoleIDs
+			}
+		}
+		return roles
+	}
+
+	for _, r := range settings.IgnoreRules {
+		if r.Event != key {
+			continue
+		}
+
+		if ruleMatches(r, s, channels, memberRoles) {
+			return r, true
+		}
+	}
+	return rule, false
+}
+
+// ruleMatches returns true if the ignore rule matches the subject.
+// channels is the subject's channel ancestry, and roles returns the subject's roles. roles is only called if the rule needs them.
+func ruleMatches(r db.IgnoreRule, s EventSubject, channels []discord.ChannelID, roles func() []discord.RoleID) bool {
+	switch {
+	case r.UserID != nil:
+		// this is the subject of the event, not whoever caused it
+		return *r.UserID == s.UserID
+	case r.RoleID != nil:
+		return *r.RoleID == s.RoleID || common.Contains(roles(), *r.RoleID)
+	case r.ChannelID != nil:
+		return common.Contains(channels, *r.ChannelID)
+	}
+	return false
+}
+
+// channelAncestry returns the subject's channel, its parent channel (for threads), and its category, without duplicates.
+func (bot *Bot) channelAncestry(ctx context.Context, s EventSubject) []discord.ChannelID {
+	channels := []discord.ChannelID{s.ChannelID}
+	add := func(id discord.ChannelID) {
+		if id.IsValid() && !common.Contains(channels, id) {
+			channels = append(channels, id)
+		}
+	}
+	add(s.ParentID)
+
+	root, err := bot.Cabinet.RootChannel(ctx, s.ChannelID)
+	if err != nil && s.ParentID.IsValid() {
+		// the channel might not be cached yet, so try its parent
+		root, err = bot.Cabinet.RootChannel(ctx, s.ParentID)
+	}
+	if err != nil {
+		return channels
+	}
+
+	add(root.ID)
+	add(root.ParentID)
+	return channels
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func TestRuleMatches(t *testing.T) {
+	userID := func(id discord.UserID) *discord.UserID { return &id }
+	roleID := func(id discord.RoleID) *discord.RoleID { return &id }
+	channelID := func(id discord.ChannelID) *discord.ChannelID { return &id }
+
+	// a thread (10) in a channel (11) in a category (12)
+	ancestry := []discord.ChannelID{10, 11, 12}
+
+	tests := []struct {
+		name      string
+		rule      db.IgnoreRule
+		subject   EventSubject
+		roles     []discord.RoleID
+		want      bool
+		wantRoles bool
+	}{
+		{
+			name: "empty rule",
+			rule: db.IgnoreRule{},
+		},
+		{
+			name:    "user",
+			rule:    db.IgnoreRule{UserID: userID(1)},
+			subject: EventSubject{UserID: 1},
+			want:    true,
+		},
+		{
+			name:    "other user",
+			rule:    db.IgnoreRule{UserID: userID(1)},
+			subject: EventSubject{UserID: 2},
+		},
+		{
+			name:    "role event",
+			rule:    db.IgnoreRule{RoleID: roleID(5)},
+			subject: EventSubject{RoleID: 5},
+			want:    true,
+		},
+		{
+			name:      "member role",
+			rule:      db.IgnoreRule{RoleID: roleID(5)},
+			subject:   EventSubject{UserID: 1},
+			roles:     []discord.RoleID{4, 5},
+			want:      true,
+			wantRoles: true,
+		},
+		{
+			name:      "other role",
+			rule:      db.IgnoreRule{RoleID: roleID(5)},
+			subject:   EventSubject{UserID: 1},
+			roles:     []discord.RoleID{4},
+			wantRoles: true,
+		},
+		{
+			name:    "channel",
+			rule:    db.IgnoreRule{ChannelID: channelID(10)},
+			subject: EventSubject{ChannelID: 10},
+			want:    true,
+		},
+		{
+			name:    "thread parent",
+			rule:    db.IgnoreRule{ChannelID: channelID(11)},
+			subject: EventSubject{ChannelID: 10},
+			want:    true,
+		},
+		{
+			name:    "category",
+			rule:    db.IgnoreRule{ChannelID: channelID(12)},
+			subject: EventSubject{ChannelID: 10},
+			want:    true,
+		},
+		{
+			name:    "other channel",
+			rule:    db.IgnoreRule{ChannelID: channelID(13)},
+			subject: EventSubject{ChannelID: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rolesFetched bool
+			roles := func() []discord.RoleID {
+				rolesFetched = true
+				return tt.roles
+			}
+
+			if got := ruleMatches(tt.rule, tt.subject, ancestry, roles); got != tt.want {
+				t.Errorf("ruleMatches() = %v, want %v", got, tt.want)
+			}
+			if rolesFetched != tt.wantRoles {
+				t.Errorf("roles fetched = %v, want %v", rolesFetched, tt.wantRoles)
+			}
+		})
+	}
+}
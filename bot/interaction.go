@@ -1,7 +1,9 @@
 package bot
 
 import (
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/bcr/v2"
 	"github.com/starshine-sys/catalogger/v2/common/log"
 )
 
@@ -16,3 +18,19 @@ func (bot *Bot) interactionCreate(ev *gateway.InteractionCreateEvent) {
 		log.Errorf("handling interaction %v: %v", ev.ID, err)
 	}
 }
+
+// Options returns the options given to the invoked command.
+// If the command is a subcommand (or in a subcommand group), the subcommand's options are returned.
+func (*Bot) Options(ctx *bcr.CommandContext) discord.CommandInteractionOptions {
+	data, ok := ctx.Event.Data.(*discord.CommandInteraction)
+	if !ok {
+		return nil
+	}
+
+	opts := discord.CommandInteractionOptions(data.Options)
+	for len(opts) == 1 &&
+		(opts[0].Type == discord.SubcommandGroupOptionType || opts[0].Type == discord.SubcommandOptionType) {
+		opts = opts[0].Options
+	}
+	return opts
+}
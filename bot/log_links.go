@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+const (
+	// linkCheckInterval is how often the creators of log links are checked for Manage Server in both linked servers.
+	linkCheckInterval = time.Hour
+	// errCodeUnknownMember is returned by Discord when a user isn't in a server.
+	errCodeUnknownMember httputil.ErrorCode = 10007
+)
+
+// logLink returns the channel in another server that the guild's logs are copied to.
+// ok is false if the guild isn't linked to another server.
+func (bot *Bot) logLink(guildID discord.GuildID) (channelID discord.ChannelID, ok bool) {
+	if !guildID.IsValid() {
+		return channelID, false
+	}
+
+	settings, err := bot.guildSettings(guildID)
+	if err != nil {
+		log.Errorf("getting log link for guild %v: %v", guildID, err)
+		return channelID, false
+	}
+	return settings.LogLink, settings.LogLink.IsValid()
+}
+
+// checkLogLinks runs CheckLogLinks every hour. It never returns.
+func (bot *Bot) checkLogLinks() {
+	ticker := time.NewTicker(linkCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		bot.CheckLogLinks()
+	}
+}
+
+// CheckLogLinks removes log links whose creator no longer has the Manage Server permission in the source server
+// or the destination server, so a server's logs stop being sent elsewhere as soon as the person who linked them loses access.
+func (bot *Bot) CheckLogLinks() {
+	links, err := bot.DB.LogLinks()
+	if err != nil {
+		log.Errorf("getting log links: %v", err)
+		return
+	}
+
+	for _, l := range links {
+		lost, err := bot.lostManageGuild(l.CreatedBy, l.SourceID, l.GuildID)
+		if err != nil {
+			log.Errorf("checking permissions for %v: %v", l.CreatedBy, err)
+			continue
+		}
+		if !lost {
+			continue
+		}
+
+		log.Infof("creator %v of log link from %v to %v lost access, removing it", l.CreatedBy, l.SourceID, l.ChannelID)
+		if _, err := bot.DB.DeleteOutgoingLogLink(l.SourceID); err != nil {
+			log.Errorf("removing log link for guild %v: %v", l.SourceID, err)
+			continue
+		}
+		bot.InvalidateGuildSettings(l.SourceID)
+	}
+}
+
+// lostManageGuild returns true if the user has certainly lost the Manage Server permission in any of the guilds.
+func (bot *Bot) lostManageGuild(userID discord.UserID, guildIDs ...discord.GuildID) (bool, error) {
+	for _, id := range guildIDs {
+		ok, err := bot.HasGuildPermission(id, userID, discord.PermissionManageGuild)
+		if err != nil {
+			// only a user who left the server is certain to have lost access
+			var httpErr *httputil.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Code == errCodeUnknownMember {
+				return true, nil
+			}
+			return false, errors.Wrapf(err, "checking permissions in guild %v", id)
+		}
+		if !ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// linkedEmbeds returns copies of the embeds with the source server's name prepended to their titles,
+// so logs from different servers can be told apart in a shared log channel.
+func (bot *Bot) linkedEmbeds(guildID discord.GuildID, embeds []discord.Embed) []discord.Embed {
+	name := guildID.String()
+	if g, err := bot.Cabinet.Guild(context.Background(), guildID); err == nil {
+		name = g.Name
+	}
+
+	out := make([]discord.Embed, len(embeds))
+	for i, e := range embeds {
+		if e.Title == "" {
+			e.Title = name
+		} else {
+			e.Title = "[" + name + "] " + e.Title
+		}
+		out[i] = e
+	}
+	return out
+}
+
+// HasGuildPermission returns true if the user has the given permission in the guild, either through their roles or by owning it.
+func (bot *Bot) HasGuildPermission(guildID discord.GuildID, userID discord.UserID, perm discord.Permissions) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	g, err := bot.Cabinet.Guild(ctx, guildID)
+	if err != nil {
+		return false, errors.Wrap(err, "getting guild")
+	}
+	if g.OwnerID == userID {
+		return true, nil
+	}
+
+	m, err := bot.Cabinet.Member(ctx, guildID, userID)
+	if err != nil {
+		mp, err := bot.Router.Rest.Member(guildID, userID)
+		if err != nil {
+			return false, errors.Wrap(err, "getting member")
+		}
+		m = *mp
+	}
+
+	roles, err := bot.Cabinet.Roles(ctx, guildID)
+	if err != nil {
+		return false, errors.Wrap(err, "getting roles")
+	}
+
+	var perms discord.Permissions
+	for _, r := range roles {
+		// the @everyone role has the same ID as the guild
+		if discord.GuildID(r.ID) == guildID || common.Contains(m.RoleIDs, r.ID) {
+			perms |= r.Permissions
+		}
+	}
+
+	return perms.Has(discord.PermissionAdministrator) || perms.Has(perm), nil
+}
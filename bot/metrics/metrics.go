@@ -35,7 +35,14 @@ type Client struct {
 	reqs   map[string]uint32
 	reqsMu sync.Mutex
 
+	flushesMu       sync.Mutex
+	flushes         uint32
+	flushLatency    time.Duration
+	flushLatencyMax time.Duration
+
 	Counts func() (guilds, channels, roles, messages int64, timeTaken time.Duration)
+	// QueueDepth returns the total number of embeds waiting to be sent.
+	QueueDepth func() int64
 }
 
 // New creates a new client
@@ -46,6 +53,7 @@ func New(url, token, organization, database string) *Client {
 		Counts: func() (int64, int64, int64, int64, time.Duration) {
 			return 0, 0, 0, 0, 0
 		},
+		QueueDepth: func() int64 { return 0 },
 	}
 
 	c.Client = influxdb2.NewClientWithOptions(url, token,
@@ -82,6 +90,21 @@ func (c *Client) IncRequests(method, path string, status int) {
 	c.reqsMu.Unlock()
 }
 
+// ObserveFlush records a queue flush, with how long its oldest embed waited to be sent.
+func (c *Client) ObserveFlush(latency time.Duration) {
+	if c == nil {
+		return
+	}
+
+	c.flushesMu.Lock()
+	c.flushes++
+	c.flushLatency += latency
+	if latency > c.flushLatencyMax {
+		c.flushLatencyMax = latency
+	}
+	c.flushesMu.Unlock()
+}
+
 // IncQuery increments the query count by one
 func (c *Client) IncQuery() {
 	if c == nil {
@@ -172,6 +195,27 @@ func (c *Client) submitInner() {
 		influxdb2.NewPoint("requests", nil, rm, time.Now()),
 	)
 
+	// queues
+	depth := c.QueueDepth()
+
+	c.flushesMu.Lock()
+	qm := map[string]interface{}{
+		"depth":   depth,
+		"flushes": c.flushes,
+	}
+	if c.flushes > 0 {
+		qm["flush_latency_avg"] = (c.flushLatency / time.Duration(c.flushes)).Milliseconds()
+		qm["flush_latency_max"] = c.flushLatencyMax.Milliseconds()
+	}
+	c.flushes = 0
+	c.flushLatency = 0
+	c.flushLatencyMax = 0
+	c.flushesMu.Unlock()
+
+	c.Client.WritePoint(
+		influxdb2.NewPoint("queues", nil, qm, time.Now()),
+	)
+
 	stats := runtime.MemStats{}
 	runtime.ReadMemStats(&stats)
 
@@ -0,0 +1,167 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// PaginateEmbeds responds to the command with the given embeds, with buttons to switch between them.
+// The response is ephemeral. This blocks until the buttons time out after 10 minutes, or the user closes the menu.
+func (bot *Bot) PaginateEmbeds(ctx *bcr.CommandContext, embeds []discord.Embed) (err error) {
+	if len(embeds) == 0 {
+		return errors.New("no embeds given")
+	}
+
+	if len(embeds) == 1 {
+		return ctx.ReplyEphemeral("", embeds[0])
+	}
+
+	page := 0
+	pageEmbed := func() []discord.Embed {
+		e := embeds[page]
+		e.Footer = &discord.EmbedFooter{
+			Text: fmt.Sprintf("Page %v/%v", page+1, len(embeds)),
+		}
+		return []discord.Embed{e}
+	}
+
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Label:    "First",
+				CustomID: "page:first",
+				Style:    discord.SecondaryButtonStyle(),
+			},
+			&discord.ButtonComponent{
+				Label:    "Previous",
+				CustomID: "page:prev",
+				Style:    discord.PrimaryButtonStyle(),
+			},
+			&discord.ButtonComponent{
+				Label:    "Next",
+				CustomID: "page:next",
+				Style:    discord.PrimaryButtonStyle(),
+			},
+			&discord.ButtonComponent{
+				Label:    "Last",
+				CustomID: "page:last",
+				Style:    discord.SecondaryButtonStyle(),
+			},
+			&discord.ButtonComponent{
+				Label:    "Close",
+				CustomID: "page:close",
+				Style:    discord.DangerButtonStyle(),
+			},
+		},
+	}
+
+	initial := pageEmbed()
+	err = ctx.ReplyComplex(api.InteractionResponseData{
+		Embeds:     &initial,
+		Components: &components,
+		Flags:      discord.EphemeralMessage,
+	})
+	if err != nil {
+		log.Errorf("sending interaction response for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "sending initial embed"))
+	}
+
+	msg, err := ctx.Original()
+	if err != nil {
+		log.Errorf("getting original message for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting original message"))
+	}
+
+	clearComponents := func() error {
+		_, err = ctx.State.EditInteractionResponse(discord.AppID(bot.Me().ID), ctx.InteractionToken, api.EditInteractionResponseData{
+			Components: &discord.ContainerComponents{},
+		})
+		return err
+	}
+
+	// timeout after 10 minutes
+	cctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	for {
+		select {
+		// if timeout, clear components and return
+		case <-cctx.Done():
+			err = clearComponents()
+			if err != nil {
+				log.Errorf("updating message for %v: %v", ctx.Event.ID, err)
+			}
+			return nil
+
+		default:
+			// wait for a button event
+			ev, ok := common.WaitFor(cctx, ctx.State, func(ev *gateway.InteractionCreateEvent) bool {
+				if ev.Message == nil || ev.Message.ID != msg.ID {
+					return false
+				}
+
+				data, ok := ev.Data.(*discord.ButtonInteraction)
+				return ok && strings.HasPrefix(string(data.CustomID), "page:")
+			})
+			if !ok {
+				continue
+			}
+
+			data, ok := ev.Data.(*discord.ButtonInteraction)
+			if !ok {
+				continue
+			}
+
+			switch data.CustomID {
+			case "page:first":
+				page = 0
+			case "page:prev":
+				page--
+				if page < 0 {
+					page = len(embeds) - 1
+				}
+			case "page:next":
+				page++
+				if page >= len(embeds) {
+					page = 0
+				}
+			case "page:last":
+				page = len(embeds) - 1
+			case "page:close":
+				err = ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+					Type: api.UpdateMessage,
+					Data: &api.InteractionResponseData{
+						Components: &discord.ContainerComponents{},
+					},
+				})
+				if err != nil {
+					log.Errorf("updating message for interaction %v: %v", ev.ID, err)
+				}
+				return nil
+			}
+
+			updated := pageEmbed()
+			err = ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+				Type: api.UpdateMessage,
+				Data: &api.InteractionResponseData{
+					Embeds:     &updated,
+					Components: &components,
+				},
+			})
+			if err != nil {
+				log.Errorf("updating message for interaction %v: %v", ev.ID, err)
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"regexp"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/pkgo/v2"
+)
+
+// DefaultPluralKitLogFormat matches the embed footer of PluralKit's log messages.
+const DefaultPluralKitLogFormat = `^System ID: (?P<system>\w{5,6}) \| Member ID: (?P<member>\w{5,6}) \| Sender: .+ \((?P<sender>\d+)\) \| Message ID: (?P<message>\d+) \| Original Message ID: (?P<original>\d+)$`
+
+// defaultPluralKitBot is PluralKit itself, used if no PluralKit-compatible bots are configured.
+var defaultPluralKitBot = PluralKitConfig{
+	UserID:    466378653216014359,
+	LogFormat: DefaultPluralKitLogFormat,
+}
+
+// logFormatGroups are the named groups that every log format must have.
+var logFormatGroups = []string{"system", "member", "sender", "message", "original"}
+
+// PluralKitBot is a PluralKit-compatible bot, with its own API session.
+type PluralKitBot struct {
+	UserID    discord.UserID
+	LogFormat *regexp.Regexp
+	API       *pkgo.Session
+}
+
+// PluralKitLog is the information parsed from a PluralKit log message.
+type PluralKitLog struct {
+	System, Member string
+
+	Sender            discord.UserID
+	MessageID         discord.MessageID
+	OriginalMessageID discord.MessageID
+}
+
+// ParseLog parses a log message embed footer.
+// ok is false if the footer doesn't match this bot's log format.
+func (pk PluralKitBot) ParseLog(footer string) (l PluralKitLog, ok bool) {
+	groups := pk.LogFormat.FindStringSubmatch(footer)
+	if groups == nil {
+		return l, false
+	}
+
+	group := func(name string) string {
+		return groups[pk.LogFormat.SubexpIndex(name)]
+	}
+
+	sender, err := discord.ParseSnowflake(group("sender"))
+	if err != nil {
+		return l, false
+	}
+	msgID, err := discord.ParseSnowflake(group("message"))
+	if err != nil {
+		return l, false
+	}
+	originalID, err := discord.ParseSnowflake(group("original"))
+	if err != nil {
+		return l, false
+	}
+
+	return PluralKitLog{
+		System:            group("system"),
+		Member:            group("member"),
+		Sender:            discord.UserID(sender),
+		MessageID:         discord.MessageID(msgID),
+		OriginalMessageID: discord.MessageID(originalID),
+	}, true
+}
+
+// newPluralKitBots creates API sessions for all configured PluralKit-compatible bots.
+func newPluralKitBots(c []PluralKitConfig, userAgent string) ([]PluralKitBot, error) {
+	if len(c) == 0 {
+		c = []PluralKitConfig{defaultPluralKitBot}
+	}
+
+	bots := make([]PluralKitBot, 0, len(c))
+	for _, pc := range c {
+		if !pc.UserID.IsValid() {
+			return nil, errors.New("PluralKit-compatible bot has no user ID set")
+		}
+
+		format := pc.LogFormat
+		if format == "" {
+			format = DefaultPluralKitLogFormat
+		}
+
+		re, err := regexp.Compile(format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "compiling log format for %v", pc.UserID)
+		}
+
+		for _, name := range logFormatGroups {
+			if re.SubexpIndex(name) == -1 {
+				return nil, errors.Errorf("log format for %v is missing the %q group", pc.UserID, name)
+			}
+		}
+
+		s := pkgo.New("")
+		if pc.APIBase != "" {
+			s.BaseURL = strings.TrimSuffix(pc.APIBase, "/")
+		}
+		s.UserAgent = userAgent
+
+		bots = append(bots, PluralKitBot{
+			UserID:    pc.UserID,
+			LogFormat: re,
+			API:       s,
+		})
+	}
+
+	return bots, nil
+}
+
+// PluralKitBot returns the PluralKit-compatible bot with the given user or application ID.
+func (bot *Bot) PluralKitBot(id discord.UserID) (pk PluralKitBot, ok bool) {
+	for _, pk := range bot.PluralKitBots {
+		if pk.UserID == id {
+			return pk, true
+		}
+	}
+	return pk, false
+}
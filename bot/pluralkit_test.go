@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestParseLog(t *testing.T) {
+	pk := PluralKitBot{LogFormat: regexp.MustCompile(DefaultPluralKitLogFormat)}
+
+	tests := []struct {
+		name   string
+		footer string
+		want   PluralKitLog
+		ok     bool
+	}{
+		{
+			name:   "valid",
+			footer: "System ID: abcde | Member ID: fghijk | Sender: user#0001 (100) | Message ID: 200 | Original Message ID: 300",
+			want:   PluralKitLog{System: "abcde", Member: "fghijk", Sender: 100, MessageID: 200, OriginalMessageID: 300},
+			ok:     true,
+		},
+		{
+			name:   "username with parentheses",
+			footer: "System ID: abcde | Member ID: fghij | Sender: user (name) (100) | Message ID: 200 | Original Message ID: 300",
+			want:   PluralKitLog{System: "abcde", Member: "fghij", Sender: 100, MessageID: 200, OriginalMessageID: 300},
+			ok:     true,
+		},
+		{
+			name:   "empty",
+			footer: "",
+		},
+		{
+			name:   "other footer",
+			footer: "Message ID: 200",
+		},
+		{
+			name:   "invalid ID",
+			footer: "System ID: abcde | Member ID: fghij | Sender: user (100) | Message ID: 99999999999999999999999 | Original Message ID: 300",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pk.ParseLog(tt.footer)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseLog(%q) = %+v, %v, want %+v, %v", tt.footer, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNewPluralKitBots(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       []PluralKitConfig
+		bots    int
+		wantErr bool
+	}{
+		{
+			name: "default",
+			bots: 1,
+		},
+		{
+			name: "default format",
+			c:    []PluralKitConfig{{UserID: 1}, {UserID: 2, APIBase: "https://example.com/v2/"}},
+			bots: 2,
+		},
+		{
+			name: "custom format",
+			c: []PluralKitConfig{{
+				UserID:    1,
+				LogFormat: `^(?P<system>\w+) (?P<member>\w+) (?P<sender>\d+) (?P<message>\d+) (?P<original>\d+)$`,
+			}},
+			bots: 1,
+		},
+		{
+			name:    "no user ID",
+			c:       []PluralKitConfig{{LogFormat: DefaultPluralKitLogFormat}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			c:       []PluralKitConfig{{UserID: 1, LogFormat: `(?P<system>`}},
+			wantErr: true,
+		},
+		{
+			name:    "missing group",
+			c:       []PluralKitConfig{{UserID: 1, LogFormat: `^(?P<system>\w+) (?P<member>\w+) (?P<sender>\d+) (?P<message>\d+)$`}},
+			wantErr: true,
+		},
+		{
+			name:    "unnamed group",
+			c:       []PluralKitConfig{{UserID: 1, LogFormat: `^(?P<system>\w+) (?P<member>\w+) (?P<sender>\d+) (?P<message>\d+) (\d+)$`}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bots, err := newPluralKitBots(tt.c, "test")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newPluralKitBots() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(bots) != tt.bots {
+				t.Errorf("newPluralKitBots() returned %d bots, want %d", len(bots), tt.bots)
+			}
+		})
+	}
+}
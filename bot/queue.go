@@ -1,25 +1,43 @@
 package bot
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/api/webhook"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/starshine-sys/catalogger/v2/common"
 	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
 )
 
 type SendData struct {
-	// If ChannelID is not valid, the channel ID is fetched by bot.Send.
-	// This should only be set if the log channel ID is checked in the log handler.
-	ChannelID discord.ChannelID
+	// If neither ChannelID nor ChannelIDs are set, the event is routed by bot.Send.
+	// These should only be set if the event was already routed in the log handler.
+	ChannelID  discord.ChannelID
+	ChannelIDs []discord.ChannelID
+	// LinkedChannelID is the channel in ChannelIDs that's in another server, from Route.LinkedChannelID.
+	LinkedChannelID discord.ChannelID
 
 	Embeds []discord.Embed
 	Files  []sendpart.File
+
+	// Subject is what the event is about, for matching ignore rules.
+	// Any fields that aren't set are filled in from the event, if possible.
+	Subject EventSubject
 }
 
 // Send either sends a slice of embeds immediately, or queues a single embed.
@@ -39,49 +57,145 @@ func (bot *Bot) Send(
 		eventName = bot.eventName(event)
 	}
 
-	// get channel ID, if not set
-	channelID := data.ChannelID
-	if !channelID.IsValid() {
-		channels, err := bot.DB.Channels(guildID)
-		if err != nil {
-			log.Errorf("getting channels for guild %v: %v", guildID, err)
+	subject := data.Subject.merge(eventSubject(event))
+
+	// dossiers are mirrored even if the event isn't logged in this server's log channels
+	if dossierEvents[eventName] {
+		go bot.SendDossier(guildID, subject.UserID, data.Embeds)
+	}
+
+	// route the event, if the handler hasn't already done so
+	channelIDs := data.ChannelIDs
+	linkedID := data.LinkedChannelID
+	if data.ChannelID.IsValid() {
+		channelIDs = append([]discord.ChannelID{data.ChannelID}, channelIDs...)
+	}
+	if len(channelIDs) == 0 {
+		route := bot.Route(guildID, eventName, subject)
+		if route.Suppressed() {
+			log.Debugf("event %v in guild %v is not logged: %v", eventName, guildID, route.Reason)
+			return
 		}
+		channelIDs = route.ChannelIDs
+		linkedID = route.LinkedChannelID
+	}
 
-		channelID = channels.Channels.For(eventName)
-		if !channelID.IsValid() {
-			log.Debugf("event %v in guild %v has no valid channel", eventName, guildID)
+	// files can only be read once, so they have to be buffered to send them to more than one channel, or to retry
+	var files func() []sendpart.File
+	if len(data.Files) > 0 {
+		var err error
+		files, err = bufferFiles(data.Files)
+		if err != nil {
+			log.Errorf("reading files for event %v in guild %v: %v", eventName, guildID, err)
 			return
 		}
 	}
 
-	// get webhook
-	wh, err := bot.getWebhook(channelID)
-	if err != nil {
-		log.Errorf("getting webhook for channel %v: %v", channelID, err)
-		return
+	ev := logEvent{
+		GuildID:  guildID,
+		Name:     eventName,
+		SourceID: subject.ChannelID,
+		Policy:   bot.queuePolicy(guildID, eventName),
+		Alert:    bot.alertRole(guildID, eventName),
 	}
 
-	// if the event should be queued to be sent in bulk, queue it and return
-	if shouldQueue[eventName] && len(data.Embeds) == 1 && len(data.Files) == 0 {
-		bot.queue(wh, eventName, data.Embeds[0])
-		return
+	for _, channelID := range channelIDs {
+		embeds := data.Embeds
+		ev := ev
+		// logs sent to another server are prefixed with this server's name
+		if linkedID.IsValid() && channelID == linkedID {
+			embeds = bot.linkedEmbeds(guildID, embeds)
+			// roles can't be pinged in another server
+			ev.Alert = discord.NullRoleID
+		}
+
+		bot.sendTo(channelID, ev, embeds, files)
 	}
+}
+
+// logEvent is an event being sent to a log channel.
+type logEvent struct {
+	GuildID discord.GuildID
+	Name    string
+	// SourceID is the channel the event happened in, if any.
+	SourceID discord.ChannelID
+	// Policy is how the event is batched.
+	Policy db.QueuePolicy
+	// Alert is the role pinged when the event is logged, if any.
+	// If it's set, the event is sent immediately.
+	Alert discord.RoleID
+}
+
+// sendTo sends or queues embeds in a single log channel. files may be nil.
+// The returned error is always nil if the embeds were queued.
+func (bot *Bot) sendTo(channelID discord.ChannelID, ev logEvent, embeds []discord.Embed, files func() []sendpart.File) error {
+	alert := ev.Alert
 
-	log.Debugf("Event for webhook %v should not be queued, sending embed", wh.ID)
+	// if the event should be queued to be sent in bulk, queue it and return
+	if ev.Policy.Enabled != nil && *ev.Policy.Enabled && len(embeds) == 1 && files == nil && !alert.IsValid() {
+		bot.queue(channelID, ev, embeds[0])
+		return nil
+	}
 
-	client := bot.webhookClient(wh)
+	log.Debugf("Event for channel %v should not be queued, sending embed", channelID)
 
-	err = client.Execute(webhook.ExecuteData{
+	data := webhook.ExecuteData{
 		AvatarURL: bot.user.AvatarURL(),
-		Embeds:    data.Embeds,
-		Files:     data.Files,
-	})
+		Embeds:    embeds,
+	}
+	if alert.IsValid() {
+		data.Content = alert.Mention()
+		data.AllowedMentions = &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+			Roles: []discord.RoleID{alert},
+		}
+	}
+
+	err := bot.deliver(channelID, data, files)
 	if err != nil {
-		log.Errorf("executing webhook %v: %v", wh.ID, err)
+		log.Errorf("sending log message to %v: %v", channelID, err)
 	}
+	bot.recordDelivery(ev.GuildID, channelID, ev.Name, 1, err)
+	return err
 }
 
-// shouldQueue is a map of all events that should be put into a webhook queue
+// alertRole returns the role that should be pinged when the event is logged in the guild, if any.
+func (bot *Bot) alertRole(guildID discord.GuildID, eventName string) discord.RoleID {
+	key, ok := common.EventKeys[eventName]
+	if !ok || !guildID.IsValid() {
+		return discord.NullRoleID
+	}
+
+	settings, err := bot.guildSettings(guildID)
+	if err != nil {
+		log.Errorf("getting alert roles for guild %v: %v", guildID, err)
+		return discord.NullRoleID
+	}
+	return settings.AlertRoles[key]
+}
+
+// bufferFiles reads the given files into memory, and returns a function that returns fresh copies of them.
+func bufferFiles(files []sendpart.File) (func() []sendpart.File, error) {
+	bufs := make([][]byte, len(files))
+	for i, f := range files {
+		b, err := io.ReadAll(f.Reader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading file %q", f.Name)
+		}
+		bufs[i] = b
+	}
+
+	return func() []sendpart.File {
+		out := make([]sendpart.File, len(files))
+		for i, f := range files {
+			out[i] = sendpart.File{Name: f.Name, Reader: bytes.NewReader(bufs[i])}
+		}
+		return out
+	}, nil
+}
+
+// shouldQueue is a map of all events that are put into a webhook queue by default.
+// This can be overridden by the bot's config and by guilds, see queuePolicy.
 var shouldQueue = map[string]bool{
 	reflect.ValueOf(&gateway.GuildMemberUpdateEvent{}).Elem().Type().Name(): true,
 	reflect.ValueOf(&gateway.MessageDeleteEvent{}).Elem().Type().Name():     true,
@@ -101,94 +215,626 @@ var shouldQueue = map[string]bool{
 	reflect.ValueOf(&gateway.GuildDeleteEvent{}).Elem().Type().Name(): true,
 }
 
-// queue is a webhook embed queue.
+// eventName returns the event's type name
+func (*Bot) eventName(i any) string {
+	return reflect.ValueOf(i).Elem().Type().Name()
+}
+
+// defaultQueuePolicy is the built-in batching policy, used for any fields not set in the bot's config or by the guild.
+var defaultQueuePolicy = db.QueuePolicy{
+	MaxEmbeds: 5,
+	MaxDelay:  5 * time.Second,
+	MaxLength: 6000,
+}
+
+const (
+	// MaxBatchEmbeds is the maximum number of embeds Discord allows in a single message.
+	MaxBatchEmbeds = 10
+	// MaxBatchLength is the maximum total length of embeds Discord allows in a single message.
+	MaxBatchLength = 6000
+	// MaxBatchDelay is the longest embeds can be held before they're sent.
+	MaxBatchDelay = 5 * time.Minute
+
+	// queueOverflow is how many low priority embeds a queue holds before the rest are collapsed into a file.
+	queueOverflow = 25
+	// queueRetryDelay is how long a queue waits before retrying after its first failed flush.
+	// This doubles with every failure in a row, up to MaxBatchDelay.
+	queueRetryDelay = 5 * time.Second
+
+	// queueChannelsKey is a Redis set of log channels with pending embeds, so they can be sent after a restart.
+	queueChannelsKey = "queue:channels"
+)
+
+// priority is how important an event is. When a queue is backed up, higher priority events are sent first,
+// and only low priority events are collapsed if it overflows.
+type priority int
+
+const (
+	priorityHigh priority = iota
+	priorityNormal
+	priorityLow
+)
+
+// priorities are all priorities, from highest to lowest.
+var priorities = [...]priority{priorityHigh, priorityNormal, priorityLow}
+
+// eventPriorities are the priorities of events that aren't normal priority.
+var eventPriorities = map[string]priority{
+	"GuildBanAddEvent":        priorityHigh,
+	"GuildBanRemoveEvent":     priorityHigh,
+	"GuildMemberKickEvent":    priorityHigh,
+	"GuildKeyRoleUpdateEvent": priorityHigh,
+	"BannedSystemEvent":       priorityHigh,
+	"GhostPingEvent":          priorityHigh,
+
+	"MessageUpdateEvent":           priorityLow,
+	"MessageDeleteEvent":           priorityLow,
+	"GuildMemberUpdateEvent":       priorityLow,
+	"GuildMemberNickUpdateEvent":   priorityLow,
+	"GuildMemberAvatarUpdateEvent": priorityLow,
+}
+
+// queuePolicy returns the batching policy for an event in the guild.
+// Policies are applied from least to most specific: the built-in default, the bot's config, then the guild's settings.
+func (bot *Bot) queuePolicy(guildID discord.GuildID, eventName string) db.QueuePolicy {
+	enabled := shouldQueue[eventName]
+	p := defaultQueuePolicy
+	p.Enabled = &enabled
+
+	key, hasKey := common.EventKeys[eventName]
+
+	p = p.Merge(bot.Config.Queue.Default)
+	if hasKey {
+		p = p.Merge(bot.Config.Queue.Events[key])
+	}
+
+	if guildID.IsValid() {
+		settings, err := bot.guildSettings(guildID)
+		if err != nil {
+			log.Errorf("getting batching policies for guild %v: %v", guildID, err)
+		} else {
+			p = p.Merge(settings.QueuePolicies[db.AllEvents])
+			if hasKey {
+				p = p.Merge(settings.QueuePolicies[key])
+			}
+		}
+	}
+
+	return clampPolicy(p)
+}
+
+// clampPolicy keeps a policy's limits within what Discord allows.
+func clampPolicy(p db.QueuePolicy) db.QueuePolicy {
+	if p.MaxEmbeds < 1 || p.MaxEmbeds > MaxBatchEmbeds {
+		p.MaxEmbeds = defaultQueuePolicy.MaxEmbeds
+	}
+	if p.MaxLength < 1 || p.MaxLength > MaxBatchLength {
+		p.MaxLength = MaxBatchLength
+	}
+	if p.MaxDelay <= 0 || p.MaxDelay > MaxBatchDelay {
+		p.MaxDelay = defaultQueuePolicy.MaxDelay
+	}
+	return p
+}
+
+func eventPriority(eventName string) priority {
+	if p, ok := eventPriorities[eventName]; ok {
+		return p
+	}
+	return priorityNormal
+}
+
+func queueKey(id discord.ChannelID, p priority) string {
+	return "queue:" + id.String() + ":" + strconv.Itoa(int(p))
+}
+
+// queuedEmbed is a single embed in a queue.
+type queuedEmbed struct {
+	GuildID discord.GuildID `json:"guild_id,omitempty"`
+	Event   string          `json:"event"`
+	// ChannelID is the channel the event happened in, if any. It's only used to describe collapsed events.
+	ChannelID discord.ChannelID `json:"channel_id,omitempty"`
+	Embed     discord.Embed     `json:"embed"`
+
+	// Queued is when the embed was queued, used for flush latency metrics.
+	Queued time.Time `json:"queued"`
+	// MaxEmbeds and MaxLength are the limits of the event's batching policy when it was queued.
+	// A message is limited by the strictest policy of all its embeds.
+	MaxEmbeds int `json:"max_embeds,omitempty"`
+	MaxLength int `json:"max_length,omitempty"`
+}
+
+// limits returns the embed's batching limits, falling back to the defaults for embeds queued without them.
+func (e queuedEmbed) limits() (embeds, length int) {
+	p := clampPolicy(db.QueuePolicy{MaxEmbeds: e.MaxEmbeds, MaxLength: e.MaxLength})
+	return p.MaxEmbeds, p.MaxLength
+}
+
+// queue is a single log channel's embed queue.
+// The embeds themselves are stored in Redis lists (one per priority), so they survive restarts;
+// they are only removed from the lists once they've been sent.
+// Embeds are added to the end of the lists at any time, but only removed while flushMu is held.
 type queue struct {
+	// flushMu is held for the whole of a flush, so only one flush per channel runs at a time.
+	// Queueing an embed doesn't need it, so log handlers never wait for a flush to finish sending.
+	flushMu sync.Mutex
+	// failures is how many flushes in a row have failed, for backing off between retries.
+	// It's only used while flushMu is held.
+	failures int
+
+	// mu protects timer and deadline. It's never held during network requests.
 	mu    sync.Mutex
-	queue []discord.Embed
 	timer *time.Timer
+	// deadline is when timer fires.
+	deadline time.Time
 }
 
-// totalLength returns the total length of all embeds in this Queue.
-func (q *queue) totalLength() (length int) {
-	for _, e := range q.queue {
-		length += e.Length()
+// retry flushes the queue again after a failed flush, waiting longer after every failure in a row. q.flushMu must be held.
+func (q *queue) retry(flush func()) time.Duration {
+	q.failures++
+
+	d := queueRetryDelay << (q.failures - 1)
+	if q.failures > 8 || d > MaxBatchDelay {
+		d = MaxBatchDelay
 	}
-	return length
+	q.schedule(d, flush)
+	return d
 }
 
-// newQueue returns a new Queue
-func newQueue() *queue {
-	return &queue{}
+// schedule flushes the queue after d, unless it's already going to be flushed sooner.
+func (q *queue) schedule(d time.Duration, flush func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deadline := time.Now().Add(d)
+	if q.timer != nil {
+		if !deadline.Before(q.deadline) {
+			return
+		}
+		q.timer.Stop()
+	}
+
+	q.timer = time.AfterFunc(d, flush)
+	q.deadline = deadline
 }
 
-// eventName returns the event's type name
-func (*Bot) eventName(i any) string {
-	return reflect.ValueOf(i).Elem().Type().Name()
+// stop cancels the queue's scheduled flush, if any.
+func (q *queue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
 }
 
-// queue queues an embed.
-func (bot *Bot) queue(wh *discord.Webhook, event string, embed discord.Embed) {
+// getQueue returns the queue for the given log channel, creating it if necessary.
+// Queues are per log channel rather than per webhook, as threads share their parent channel's webhook.
+func (bot *Bot) getQueue(channelID discord.ChannelID) *queue {
 	bot.queuesMu.Lock()
-	q, ok := bot.queues[wh.ID]
+	defer bot.queuesMu.Unlock()
+
+	q, ok := bot.queues[channelID]
 	if !ok {
-		log.Debugf("creating new embed queue for %v", wh.ID)
-		q = newQueue()
-		bot.queues[wh.ID] = q
+		log.Debugf("creating new embed queue for %v", channelID)
+		q = &queue{}
+		bot.queues[channelID] = q
 	}
-	bot.queuesMu.Unlock()
+	return q
+}
 
-	client := bot.webhookClient(wh)
+// queue queues an embed, following the event's batching policy.
+func (bot *Bot) queue(channelID discord.ChannelID, ev logEvent, embed discord.Embed) {
+	b, err := json.Marshal(queuedEmbed{
+		GuildID:   ev.GuildID,
+		Event:     ev.Name,
+		ChannelID: ev.SourceID,
+		Embed:     embed,
+		Queued:    time.Now(),
+		MaxEmbeds: ev.Policy.MaxEmbeds,
+		MaxLength: ev.Policy.MaxLength,
+	})
+	if err != nil {
+		log.Errorf("marshaling embed for %v: %v", ev.Name, err)
+		return
+	}
 
-	log.Debugf("Adding embed to queue for %v", wh.ID)
+	q := bot.getQueue(channelID)
 
-	q.mu.Lock()
-	defer q.mu.Unlock()
-	if q.totalLength()+embed.Length() >= 6000 || len(q.queue) >= 5 {
-		embeds := q.queue
-		q.queue = nil
+	log.Debugf("Adding embed to queue for %v", channelID)
 
-		if q.timer != nil {
-			q.timer.Stop()
-			q.timer = nil
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		if err := bot.queueInner(client, embeds); err != nil {
-			log.Errorf("executing queue for %v: %v", wh.ID, err)
-		}
+	var length int
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(&length, "RPUSH", queueKey(channelID, eventPriority(ev.Name)), string(b)))
+	if err != nil {
+		log.Errorf("adding embed to queue for %v: %v", channelID, err)
+		return
+	}
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(nil, "SADD", queueChannelsKey, channelID.String()))
+	if err != nil {
+		log.Errorf("marking queue for %v as pending: %v", channelID, err)
 	}
 
-	q.queue = append(q.queue, embed)
+	// full queues are flushed right away, but not here: flushing can take a while, and the caller is a log handler
+	if length >= ev.Policy.MaxEmbeds {
+		q.schedule(0, func() { bot.flushQueue(channelID) })
+		return
+	}
 
-	if q.timer == nil {
-		q.timer = time.AfterFunc(5*time.Second, func() {
-			q.mu.Lock()
-			q.timer = nil
-			embeds := q.queue
-			q.queue = nil
-			q.mu.Unlock()
+	q.schedule(ev.Policy.MaxDelay, func() { bot.flushQueue(channelID) })
+}
+
+// flushQueue sends all pending embeds for the given log channel.
+func (bot *Bot) flushQueue(channelID discord.ChannelID) {
+	q := bot.getQueue(channelID)
+
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+
+	bot.flushLocked(channelID, q)
+}
+
+// flushLocked sends all pending embeds for the given log channel, highest priority first. q.flushMu must be held.
+// If the channel's webhook is rate limited, or sending fails, the remaining embeds are kept
+// and the queue is flushed again later.
+func (bot *Bot) flushLocked(channelID discord.ChannelID, q *queue) {
+	// embeds queued from now on are either sent by this flush, or schedule a new one
+	q.stop()
+	flush := func() { bot.flushQueue(channelID) }
 
-			if len(embeds) == 0 {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for {
+		// if the webhook is rate limited, wait for it to reset instead of blocking here
+		if wh, _, err := bot.getWebhook(channelID); err == nil {
+			if wait := bot.rateLimits.wait(wh.ID); wait > 0 {
+				log.Debugf("webhook for %v is rate limited, flushing queue again in %v", channelID, wait)
+				q.schedule(wait, flush)
 				return
 			}
+		}
 
-			if err := bot.queueInner(client, embeds); err != nil {
-				log.Errorf("executing queue for %v: %v", wh.ID, err)
+		err := bot.collapseOverflow(ctx, channelID)
+		if err != nil {
+			log.Errorf("collapsing overflowing queue for %v, retrying in %v: %v", channelID, q.retry(flush), err)
+			return
+		}
+
+		batch, taken, err := bot.nextBatch(ctx, channelID)
+		if err != nil {
+			log.Errorf("getting queued embeds for %v, retrying in %v: %v", channelID, q.retry(flush), err)
+			return
+		}
+
+		if taken == [len(priorities)]int{} {
+			err = bot.DB.Redis.Do(ctx, radix.Cmd(nil, "SREM", queueChannelsKey, channelID.String()))
+			if err != nil {
+				log.Errorf("marking queue for %v as empty: %v", channelID, err)
+			}
+			return
+		}
+
+		if len(batch) > 0 {
+			embeds := make([]discord.Embed, len(batch))
+			queued := batch[0].Queued
+			for i, e := range batch {
+				embeds[i] = e.Embed
+				if e.Queued.Before(queued) {
+					queued = e.Queued
+				}
+			}
+
+			err := bot.queueInner(channelID, embeds)
+			if err != nil {
+				// if sending can never succeed, the embeds are dropped, so they don't block the queue forever
+				if !isPermanentFailure(err) {
+					wait := q.retry(flush)
+					// the embeds are retried, so the failure is only recorded once the queue keeps failing
+					if q.failures >= healthMinFailures {
+						bot.recordBatch(channelID, batch, err)
+					}
+					log.Errorf("executing queue for %v, retrying in %v: %v", channelID, wait, err)
+					return
+				}
+				log.Errorf("executing queue for %v, dropping %v embed(s): %v", channelID, len(batch), err)
+				// if this was a deleted dossier thread, the member's next event creates a new one
+				bot.forgetDossierThread(channelID, err)
+			} else {
+				bot.Metrics.ObserveFlush(time.Since(queued))
+			}
+			bot.recordBatch(channelID, batch, err)
+			q.failures = 0
+		}
+
+		for _, p := range priorities {
+			if taken[p] == 0 {
+				continue
+			}
+
+			err = bot.DB.Redis.Do(ctx, radix.Cmd(nil, "LTRIM", queueKey(channelID, p), strconv.Itoa(taken[p]), "-1"))
+			if err != nil {
+				log.Errorf("removing sent embeds from queue for %v: %v", channelID, err)
 				return
 			}
+		}
+	}
+}
+
+// nextBatch returns as many queued embeds as fit in a single message, highest priority first,
+// and how many entries were taken from the front of each priority's list.
+func (bot *Bot) nextBatch(ctx context.Context, channelID discord.ChannelID) (batch []queuedEmbed, taken [len(priorities)]int, err error) {
+	var lists [len(priorities)][]string
+	for _, p := range priorities {
+		err = bot.DB.Redis.Do(ctx, radix.Cmd(&lists[p], "LRANGE", queueKey(channelID, p), "0", strconv.Itoa(MaxBatchEmbeds-1)))
+		if err != nil {
+			return nil, taken, err
+		}
+	}
+
+	batch, taken = takeBatch(channelID, lists)
+	return batch, taken, nil
+}
+
+// takeBatch takes as many embeds from the front of each priority's raw queue as fit in a single message,
+// highest priority first. The batch is limited by the strictest policy of the embeds in it.
+func takeBatch(channelID discord.ChannelID, lists [len(priorities)][]string) (batch []queuedEmbed, taken [len(priorities)]int) {
+	var length int
+	limitEmbeds, limitLength := MaxBatchEmbeds, MaxBatchLength
+
+	for _, p := range priorities {
+		for _, s := range lists[p] {
+			var e queuedEmbed
+			if err := json.Unmarshal([]byte(s), &e); err != nil {
+				// this should never happen, but the embed is dropped so it doesn't block the queue
+				log.Errorf("unmarshaling queued embed for %v: %v", channelID, err)
+				taken[p]++
+				continue
+			}
+
+			eEmbeds, eLength := e.limits()
+			if eEmbeds < limitEmbeds {
+				limitEmbeds = eEmbeds
+			}
+			if eLength < limitLength {
+				limitLength = eLength
+			}
+
+			if len(batch) > 0 && (len(batch) >= limitEmbeds || length+e.Embed.Length() > limitLength) {
+				return batch, taken
+			}
+			batch = append(batch, e)
+			length += e.Embed.Length()
+			taken[p]++
+		}
+	}
+	return batch, taken
+}
+
+// collapseOverflow collapses low priority embeds into files if the queue has more than queueOverflow of them.
+// The first few embeds are still sent normally, and the rest are summarized per event and channel, like
+// "...and 143 more deleted messages in #general", with the full logs attached.
+func (bot *Bot) collapseOverflow(ctx context.Context, channelID discord.ChannelID) error {
+	key := queueKey(channelID, priorityLow)
+
+	var n int
+	err := bot.DB.Redis.Do(ctx, radix.Cmd(&n, "LLEN", key))
+	if err != nil {
+		return errors.Wrap(err, "getting queue length")
+	}
+	if n <= queueOverflow {
+		return nil
+	}
+
+	var raw []string
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(&raw, "LRANGE", key, strconv.Itoa(defaultQueuePolicy.MaxEmbeds), "-1"))
+	if err != nil {
+		return errors.Wrap(err, "getting overflowing embeds")
+	}
+
+	log.Debugf("queue for %v is overflowing, collapsing %v embeds", channelID, len(raw))
+
+	type group struct {
+		guildID   discord.GuildID
+		event     string
+		channelID discord.ChannelID
+		count     int
+		text      strings.Builder
+	}
+	var groups []*group
+
+	for _, s := range raw {
+		var e queuedEmbed
+		if err := json.Unmarshal([]byte(s), &e); err != nil {
+			continue
+		}
+
+		var g *group
+		for _, existing := range groups {
+			if existing.guildID == e.GuildID && existing.event == e.Event && existing.channelID == e.ChannelID {
+				g = existing
+				break
+			}
+		}
+		if g == nil {
+			g = &group{guildID: e.GuildID, event: e.Event, channelID: e.ChannelID}
+			groups = append(groups, g)
+		}
+
+		g.count++
+		g.text.WriteString(embedText(e.Embed))
+		g.text.WriteString("\n\n")
+	}
+
+	// every group gets its own embed and file, and a message can have at most 10 of each
+	for i := 0; i < len(groups); i += 10 {
+		end := i + 10
+		if end > len(groups) {
+			end = len(groups)
+		}
+
+		var (
+			embeds []discord.Embed
+			texts  []string
+			names  []string
+		)
+		for _, g := range groups[i:end] {
+			name := strings.ToLower(common.EventName(common.EventKeys[g.event]))
+			title := fmt.Sprintf("…and %v more %v", g.count, name)
+			if g.channelID.IsValid() {
+				title += " in #" + bot.channelName(g.channelID)
+			}
+
+			embeds = append(embeds, discord.Embed{
+				Title:       title,
+				Description: "Too many events were logged at once, so they were collapsed into the attached file.",
+				Color:       common.ColourOrange,
+				Timestamp:   discord.NowTimestamp(),
+			})
+			texts = append(texts, g.text.String())
+			names = append(names, fmt.Sprintf("%v-%v.txt", strings.ReplaceAll(name, " ", "-"), g.channelID))
+		}
+
+		err = bot.deliverQueued(channelID, webhook.ExecuteData{
+			AvatarURL:       bot.user.AvatarURL(),
+			Embeds:          embeds,
+			AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+		}, func() []sendpart.File {
+			files := make([]sendpart.File, len(texts))
+			for i := range texts {
+				files[i] = sendpart.File{Name: names[i], Reader: strings.NewReader(texts[i])}
+			}
+			return files
 		})
+		// the embeds are retried after transient errors, so those aren't recorded
+		if err != nil && !isPermanentFailure(err) {
+			return errors.Wrap(err, "sending collapsed embeds")
+		}
+		for _, g := range groups[i:end] {
+			bot.recordDelivery(g.guildID, channelID, g.event, g.count, err)
+		}
+	}
+
+	// new embeds may have been added to the end of the queue in the meantime, so only the collapsed range is removed
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(nil,
+		"EVAL", removeRangeScript, "1", key, strconv.Itoa(defaultQueuePolicy.MaxEmbeds), strconv.Itoa(len(raw)),
+	))
+	if err != nil {
+		return errors.Wrap(err, "removing collapsed embeds")
+	}
+	return nil
+}
+
+// removeRangeScript removes ARGV[2] entries from the list KEYS[1], starting at index ARGV[1].
+// Entries are only removed from a queue while its flushMu is held, so the indexes don't shift while it runs;
+// the entries are replaced with a placeholder, which is then removed.
+const removeRangeScript = `
+local start, count = tonumber(ARGV[1]), tonumber(ARGV[2])
+for i = start, start + count - 1 do
+	redis.call("LSET", KEYS[1], i, "__removed__")
+end
+return redis.call("LREM", KEYS[1], count, "__removed__")
+`
+
+// channelName returns the name of the given channel, or its ID if it isn't cached.
+func (bot *Bot) channelName(id discord.ChannelID) string {
+	ch, err := bot.Cabinet.Channel(context.Background(), id)
+	if err != nil {
+		return id.String()
+	}
+	return ch.Name
+}
+
+// embedText renders an embed as plain text, for collapsed logs.
+func embedText(e discord.Embed) string {
+	var b strings.Builder
+	if e.Title != "" {
+		b.WriteString(e.Title + "\n")
 	}
+	if e.Author != nil {
+		b.WriteString(e.Author.Name + "\n")
+	}
+	if e.Description != "" {
+		b.WriteString(e.Description + "\n")
+	}
+	for _, f := range e.Fields {
+		b.WriteString(f.Name + ": " + f.Value + "\n")
+	}
+	if e.Footer != nil {
+		b.WriteString(e.Footer.Text + "\n")
+	}
+	if e.Timestamp.IsValid() {
+		b.WriteString(e.Timestamp.Time().UTC().Format(time.RFC3339) + "\n")
+	}
+	return strings.TrimSpace(b.String())
 }
 
-func (bot *Bot) queueInner(client *webhook.Client, embeds []discord.Embed) (err error) {
-	log.Debugf("Executing webhook %v, with %v embed(s)", client.ID, len(embeds))
+// FlushQueues sends all pending embeds in every queue, including those left over from before a restart.
+func (bot *Bot) FlushQueues() {
+	var ids []string
+	err := bot.DB.Redis.Do(context.Background(), radix.Cmd(&ids, "SMEMBERS", queueChannelsKey))
+	if err != nil {
+		log.Errorf("getting pending queues: %v", err)
+		return
+	}
+
+	if len(ids) > 0 {
+		log.Infof("flushing %v pending embed queue(s)", len(ids))
+	}
+
+	for _, s := range ids {
+		sf, err := discord.ParseSnowflake(s)
+		if err != nil {
+			log.Errorf("parsing queued channel ID %q: %v", s, err)
+			continue
+		}
+		bot.flushQueue(discord.ChannelID(sf))
+	}
+}
 
-	_, err = client.ExecuteAndWait(webhook.ExecuteData{
+// queueDepth returns the total number of embeds waiting in all queues.
+func (bot *Bot) queueDepth() (depth int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var ids []string
+	err := bot.DB.Redis.Do(ctx, radix.Cmd(&ids, "SMEMBERS", queueChannelsKey))
+	if err != nil {
+		log.Errorf("getting pending queues: %v", err)
+		return 0
+	}
+
+	for _, s := range ids {
+		sf, err := discord.ParseSnowflake(s)
+		if err != nil {
+			continue
+		}
+
+		for _, p := range priorities {
+			var n int64
+			err = bot.DB.Redis.Do(ctx, radix.Cmd(&n, "LLEN", queueKey(discord.ChannelID(sf), p)))
+			if err != nil {
+				log.Errorf("getting length of queue for %v: %v", s, err)
+				continue
+			}
+			depth += n
+		}
+	}
+	return depth
+}
+
+func (bot *Bot) queueInner(channelID discord.ChannelID, embeds []discord.Embed) (err error) {
+	log.Debugf("Executing webhook for %v, with %v embed(s)", channelID, len(embeds))
+
+	return bot.deliverQueued(channelID, webhook.ExecuteData{
 		AvatarURL: bot.user.AvatarURL(),
 		Embeds:    embeds,
 		// won't ping anyway because it's all embeds, but can't hurt
 		AllowedMentions: &api.AllowedMentions{
 			Parse: []api.AllowedMentionType{},
 		},
-	})
-	return
+	}, nil)
 }
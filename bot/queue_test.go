@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func TestClampPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		in   db.QueuePolicy
+		want db.QueuePolicy
+	}{
+		{
+			name: "unset",
+			in:   db.QueuePolicy{},
+			want: db.QueuePolicy{MaxEmbeds: defaultQueuePolicy.MaxEmbeds, MaxDelay: defaultQueuePolicy.MaxDelay, MaxLength: MaxBatchLength},
+		},
+		{
+			name: "within limits",
+			in:   db.QueuePolicy{MaxEmbeds: 3, MaxDelay: time.Minute, MaxLength: 2000},
+			want: db.QueuePolicy{MaxEmbeds: 3, MaxDelay: time.Minute, MaxLength: 2000},
+		},
+		{
+			name: "at limits",
+			in:   db.QueuePolicy{MaxEmbeds: MaxBatchEmbeds, MaxDelay: MaxBatchDelay, MaxLength: MaxBatchLength},
+			want: db.QueuePolicy{MaxEmbeds: MaxBatchEmbeds, MaxDelay: MaxBatchDelay, MaxLength: MaxBatchLength},
+		},
+		{
+			name: "over limits",
+			in:   db.QueuePolicy{MaxEmbeds: MaxBatchEmbeds + 1, MaxDelay: MaxBatchDelay + time.Second, MaxLength: MaxBatchLength + 1},
+			want: db.QueuePolicy{MaxEmbeds: defaultQueuePolicy.MaxEmbeds, MaxDelay: defaultQueuePolicy.MaxDelay, MaxLength: MaxBatchLength},
+		},
+		{
+			name: "negative",
+			in:   db.QueuePolicy{MaxEmbeds: -1, MaxDelay: -time.Second, MaxLength: -1},
+			want: db.QueuePolicy{MaxEmbeds: defaultQueuePolicy.MaxEmbeds, MaxDelay: defaultQueuePolicy.MaxDelay, MaxLength: MaxBatchLength},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampPolicy(tt.in); got != tt.want {
+				t.Errorf("clampPolicy(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// rawEmbed returns a queued embed as it's stored in Redis, with a description of the given length.
+func rawEmbed(t *testing.T, event string, length, maxEmbeds, maxLength int) string {
+	t.Helper()
+
+	b, err := json.Marshal(queuedEmbed{
+		Event:     event,
+		Embed:     discord.Embed{Description: strings.Repeat("a", length)},
+		MaxEmbeds: maxEmbeds,
+		MaxLength: maxLength,
+	})
+	if err != nil {
+		t.Fatalf("marshaling embed: %v", err)
+	}
+	return string(b)
+}
+
+func TestTakeBatch(t *testing.T) {
+	repeat := func(s string, n int) (out []string) {
+		for i := 0; i < n; i++ {
+			out = append(out, s)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		lists  [len(priorities)][]string
+		events []string
+		taken  [len(priorities)]int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "highest priority first",
+			lists: [len(priorities)][]string{
+				priorityHigh: {rawEmbed(t, "high", 10, 10, 0)},
+				priorityLow:  {rawEmbed(t, "low", 10, 10, 0)},
+			},
+			events: []string{"high", "low"},
+			taken:  [len(priorities)]int{priorityHigh: 1, priorityLow: 1},
+		},
+		{
+			name: "default embed limit",
+			lists: [len(priorities)][]string{
+				priorityNormal: repeat(rawEmbed(t, "normal", 10, 0, 0), MaxBatchEmbeds),
+			},
+			events: repeat("normal", defaultQueuePolicy.MaxEmbeds),
+			taken:  [len(priorities)]int{priorityNormal: defaultQueuePolicy.MaxEmbeds},
+		},
+		{
+			name: "strictest embed limit",
+			lists: [len(priorities)][]string{
+				priorityHigh:   {rawEmbed(t, "high", 10, 10, 0)},
+				priorityNormal: repeat(rawEmbed(t, "normal", 10, 2, 0), 3),
+			},
+			events: []string{"high", "normal"},
+			taken:  [len(priorities)]int{priorityHigh: 1, priorityNormal: 1},
+		},
+		{
+			name: "length limit",
+			lists: [len(priorities)][]string{
+				priorityNormal: repeat(rawEmbed(t, "normal", 2500, 10, 0), 3),
+			},
+			events: []string{"normal", "normal"},
+			taken:  [len(priorities)]int{priorityNormal: 2},
+		},
+		{
+			name: "oversized embed is sent alone",
+			lists: [len(priorities)][]string{
+				priorityNormal: repeat(rawEmbed(t, "normal", 200, 10, 100), 2),
+			},
+			events: []string{"normal"},
+			taken:  [len(priorities)]int{priorityNormal: 1},
+		},
+		{
+			name: "invalid embeds are dropped",
+			lists: [len(priorities)][]string{
+				priorityHigh: {"not json", rawEmbed(t, "high", 10, 10, 0)},
+			},
+			events: []string{"high"},
+			taken:  [len(priorities)]int{priorityHigh: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, taken := takeBatch(discord.NullChannelID, tt.lists)
+
+			var events []string
+			for _, e := range batch {
+				events = append(events, e.Event)
+			}
+
+			if strings.Join(events, ",") != strings.Join(tt.events, ",") {
+				t.Errorf("batch = %v, want %v", events, tt.events)
+			}
+			if taken != tt.taken {
+				t.Errorf("taken = %v, want %v", taken, tt.taken)
+			}
+		})
+	}
+}
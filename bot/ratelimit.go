@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil/httpdriver"
+)
+
+var webhookPath = regexp.MustCompile(`/webhooks/(\d+)/[^/]+`)
+
+// webhookBucket is the rate limit state of a single webhook, as reported by Discord's response headers.
+type webhookBucket struct {
+	remaining int
+	reset     time.Time
+}
+
+// rateLimits tracks webhook rate limit buckets, so queues can wait for a bucket to reset
+// instead of sending requests that will only be held up by the REST client's rate limiter.
+type rateLimits struct {
+	mu      sync.Mutex
+	buckets map[discord.WebhookID]webhookBucket
+}
+
+// track updates the bucket for a webhook execute request from its response headers.
+func (rl *rateLimits) track(path string, h httpdriver.Header) {
+	match := webhookPath.FindStringSubmatch(path)
+	if match == nil {
+		return
+	}
+
+	sf, err := discord.ParseSnowflake(match[1])
+	if err != nil {
+		return
+	}
+
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetAfter, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.buckets == nil {
+		rl.buckets = map[discord.WebhookID]webhookBucket{}
+	}
+	rl.buckets[discord.WebhookID(sf)] = webhookBucket{
+		remaining: remaining,
+		reset:     time.Now().Add(time.Duration(resetAfter * float64(time.Second))),
+	}
+}
+
+// wait returns how long to wait before the webhook can be executed again, or 0 if it can be executed now.
+func (rl *rateLimits) wait(id discord.WebhookID) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[id]
+	if !ok || b.remaining > 0 {
+		return 0
+	}
+
+	wait := time.Until(b.reset)
+	if wait <= 0 {
+		delete(rl.buckets, id)
+		return 0
+	}
+	return wait
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestRateLimits(t *testing.T) {
+	const id = discord.WebhookID(123)
+
+	header := func(remaining, resetAfter string) http.Header {
+		h := http.Header{}
+		if remaining != "" {
+			h.Set("X-RateLimit-Remaining", remaining)
+		}
+		if resetAfter != "" {
+			h.Set("X-RateLimit-Reset-After", resetAfter)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		header  http.Header
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{
+			name:    "exhausted",
+			path:    "/api/v10/webhooks/123/token",
+			header:  header("0", "1.5"),
+			minWait: time.Second,
+			maxWait: 1500 * time.Millisecond,
+		},
+		{
+			name:   "requests remaining",
+			path:   "/api/v10/webhooks/123/token",
+			header: header("2", "1.5"),
+		},
+		{
+			name:   "already reset",
+			path:   "/api/v10/webhooks/123/token",
+			header: header("0", "0"),
+		},
+		{
+			name:   "other webhook",
+			path:   "/api/v10/webhooks/456/token",
+			header: header("0", "1.5"),
+		},
+		{
+			name:   "not a webhook",
+			path:   "/api/v10/channels/123/messages",
+			header: header("0", "1.5"),
+		},
+		{
+			name:   "missing headers",
+			path:   "/api/v10/webhooks/123/token",
+			header: header("", ""),
+		},
+		{
+			name:   "invalid headers",
+			path:   "/api/v10/webhooks/123/token",
+			header: header("none", "soon"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rl rateLimits
+			rl.track(tt.path, tt.header)
+
+			if wait := rl.wait(id); wait < tt.minWait || wait > tt.maxWait {
+				t.Errorf("wait = %v, want between %v and %v", wait, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
+
+func TestRateLimitsExpire(t *testing.T) {
+	const id = discord.WebhookID(123)
+
+	var rl rateLimits
+	rl.buckets = map[discord.WebhookID]webhookBucket{
+		id: {remaining: 0, reset: time.Now().Add(-time.Second)},
+	}
+
+	if wait := rl.wait(id); wait != 0 {
+		t.Errorf("wait = %v, want 0", wait)
+	}
+	if _, ok := rl.buckets[id]; ok {
+		t.Errorf("expired bucket wasn't removed")
+	}
+}
@@ -28,6 +28,8 @@ func (bot *Bot) onResponse(req httpdriver.Request, resp httpdriver.Response) err
 
 	log.Debugf("%v %v => %v", method, metrics.LoggingName(req.GetPath()), resp.GetStatus())
 
+	bot.rateLimits.track(req.GetPath(), resp.GetHeader())
+
 	go bot.Metrics.IncRequests(method, req.GetPath(), resp.GetStatus())
 
 	return nil
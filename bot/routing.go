@@ -0,0 +1,196 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// messageEvents are the events that honour ignored channels, ignored users and roles, and redirects.
+var messageEvents = map[string]bool{
+	"MessageUpdateEvent":     true,
+	"MessageDeleteEvent":     true,
+	"MessageDeleteBulkEvent": true,
+	"GhostPingEvent":         true,
+}
+
+// Route is where an event should be logged, or why it shouldn't be.
+type Route struct {
+	// ChannelID is the main channel the event should be logged in.
+	// If it's not valid, the event is suppressed, and Reason says why.
+	ChannelID discord.ChannelID
+	// ChannelIDs are all channels the event should be logged in, starting with ChannelID.
+	ChannelIDs []discord.ChannelID
+	// Reason is a short explanation of why the event was suppressed or sent to ChannelID.
+	Reason string
+	// Steps explains every check made while routing the event, in order.
+	Steps []string
+	// LinkedChannelID is the channel in another server that the server's logs are copied to, if any.
+	// It's also in ChannelIDs.
+	LinkedChannelID discord.ChannelID
+
+	// Channels are the event's channel, its parent channel (for threads), and its category.
+	Channels []discord.ChannelID
+}
+
+// Suppressed returns true if the event should not be logged.
+func (r Route) Suppressed() bool { return !r.ChannelID.IsValid() }
+
+func (r *Route) step(format string, args ...any) {
+	r.Steps = append(r.Steps, fmt.Sprintf(format, args...))
+}
+
+func (r Route) suppress(format string, args ...any) Route {
+	r.ChannelID = discord.NullChannelID
+	r.ChannelIDs = nil
+	r.LinkedChannelID = discord.NullChannelID
+	r.Reason = fmt.Sprintf(format, args...)
+	r.Steps = append(r.Steps, "Suppressed: "+r.Reason)
+	return r
+}
+
+// Route returns where the given event should be logged in the guild, or why it shouldn't be logged.
+// This checks ignore rules, ignored channels, users, and roles, the event's log channels, and redirects.
+func (bot *Bot) Route(guildID discord.GuildID, eventName string, s EventSubject) Route {
+	lc, err := bot.DB.Channels(guildID)
+	if err != nil {
+		log.Errorf("getting channels for guild %v: %v", guildID, err)
+		return Route{}.suppress("the server's log channels couldn't be fetched")
+	}
+
+	return bot.RouteWith(lc, guildID, eventName, s)
+}
+
+// RouteWith is like Route, but uses already fetched log channels.
+func (bot *Bot) RouteWith(lc db.Channels, guildID discord.GuildID, eventName string, s EventSubject) (r Route) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if s.ChannelID.IsValid() {
+		r.Channels = bot.channelAncestry(ctx, s)
+	}
+
+	// ignore rules apply to every event
+	if rule, ok := bot.matchIgnoreRule(ctx, guildID, eventName, s, r.Channels); ok {
+		return r.suppress("it matches ignore rule #%v", rule.ID)
+	}
+	r.step("No ignore rules match.")
+
+	if messageEvents[eventName] {
+		for _, id := range r.Channels {
+			if common.Contains(lc.Ignores.GlobalChannels, id) {
+				return r.suppress("the channel %v is ignored", id.Mention())
+			}
+		}
+		if len(r.Channels) > 0 {
+			r.step("The channel isn't ignored.")
+		}
+
+		if s.UserID.IsValid() {
+			if reason := bot.userIgnoredReason(ctx, lc, guildID, r.Channels, s.UserID); reason != "" {
+				return r.suppress(reason)
+			}
+			r.step("The user isn't ignored.")
+		}
+	}
+
+	r.ChannelID = lc.Channels.For(eventName)
+	if !r.ChannelID.IsValid() {
+		return r.suppress("no log channel is set for this event")
+	}
+	r.Reason = fmt.Sprintf("it's logged in %v", r.ChannelID.Mention())
+	r.step("The event's log channel is %v.", r.ChannelID.Mention())
+
+	// redirects are checked from most to least specific: the channel, its parent (for threads), then its category
+	if messageEvents[eventName] {
+		if id, target, ok := redirectFor(lc.Redirects, r.Channels); ok {
+			r.ChannelID = target
+			r.Reason = fmt.Sprintf("logs for %v are redirected to %v", id.Mention(), target.Mention())
+			r.step("Logs for %v are redirected to %v.", id.Mention(), target.Mention())
+		}
+	}
+	r.ChannelIDs = []discord.ChannelID{r.ChannelID}
+
+	// extra channels always get the event, regardless of redirects
+	for _, id := range lc.Channels.ExtraFor(eventName) {
+		if id.IsValid() && !common.Contains(r.ChannelIDs, id) {
+			r.ChannelIDs = append(r.ChannelIDs, id)
+			r.step("The event is also logged in %v.", id.Mention())
+		}
+	}
+
+	if id, ok := bot.logLink(guildID); ok && !common.Contains(r.ChannelIDs, id) {
+		r.ChannelIDs = append(r.ChannelIDs, id)
+		r.LinkedChannelID = id
+		r.step("This server's logs are copied to %v in another server.", id.Mention())
+	}
+
+	return r
+}
+
+// redirectFor returns the first of the channels that has its logs redirected, and the channel they're redirected to.
+// channels should be ordered from most to least specific, like Route.Channels.
+func redirectFor(redirects db.Redirects, channels []discord.ChannelID) (from, to discord.ChannelID, ok bool) {
+	for _, id := range channels {
+		if target, ok := redirects[id.String()]; ok && target.IsValid() {
+			return id, target, true
+		}
+	}
+	return discord.NullChannelID, discord.NullChannelID, false
+}
+
+// UserIgnored returns true if the user's messages in the given channels are ignored, either directly or through one of their roles.
+// channels should be Route.Channels.
+func (bot *Bot) UserIgnored(lc db.Channels, guildID discord.GuildID, channels []discord.ChannelID, userID discord.UserID) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return bot.userIgnoredReason(ctx, lc, guildID, channels, userID) != ""
+}
+
+// userIgnoredReason returns why the user's messages in the given channels are ignored, or an empty string if they aren't.
+func (bot *Bot) userIgnoredReason(ctx context.Context, lc db.Channels, guildID discord.GuildID, channels []discord.ChannelID, userID discord.UserID) string {
+	if common.Contains(lc.Ignores.GlobalUsers, userID) {
+		return fmt.Sprintf("the user %v is ignored", userID.Mention())
+	}
+
+	for _, id := range channels {
+		if common.Contains(lc.Ignores.PerChannel[id.String()], userID) {
+			return fmt.Sprintf("the user %v is ignored in %v", userID.Mention(), id.Mention())
+		}
+	}
+
+	// only look up the member if any roles are ignored
+	hasRoles := len(lc.Ignores.GlobalRoles) > 0
+	for _, id := range channels {
+		hasRoles = hasRoles || len(lc.Ignores.PerChannelRoles[id.String()]) > 0
+	}
+	if !hasRoles {
+		return ""
+	}
+
+	m, err := bot.Cabinet.Member(ctx, guildID, userID)
+	if err != nil {
+		// the user probably left or is a webhook, so there are no roles to check
+		log.Debugf("getting member %v in %v to check ignored roles: %v", userID, guildID, err)
+		return ""
+	}
+
+	for _, role := range m.RoleIDs {
+		if common.Contains(lc.Ignores.GlobalRoles, role) {
+			return fmt.Sprintf("the user has the ignored role %v", role.Mention())
+		}
+
+		for _, id := range channels {
+			if common.Contains(lc.Ignores.PerChannelRoles[id.String()], role) {
+				return fmt.Sprintf("the user has the role %v, which is ignored in %v", role.Mention(), id.Mention())
+			}
+		}
+	}
+	return ""
+}
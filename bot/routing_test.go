@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func TestRedirectFor(t *testing.T) {
+	// a thread (10) in a channel (11) in a category (12)
+	ancestry := []discord.ChannelID{10, 11, 12}
+
+	tests := []struct {
+		name      string
+		redirects db.Redirects
+		from, to  discord.ChannelID
+		ok        bool
+	}{
+		{
+			name: "no redirects",
+		},
+		{
+			name:      "unrelated channel",
+			redirects: db.Redirects{"13": 20},
+		},
+		{
+			name:      "channel",
+			redirects: db.Redirects{"10": 20},
+			from:      10, to: 20, ok: true,
+		},
+		{
+			name:      "thread parent",
+			redirects: db.Redirects{"11": 21},
+			from:      11, to: 21, ok: true,
+		},
+		{
+			name:      "category",
+			redirects: db.Redirects{"12": 22},
+			from:      12, to: 22, ok: true,
+		},
+		{
+			name:      "channel before parent and category",
+			redirects: db.Redirects{"10": 20, "11": 21, "12": 22},
+			from:      10, to: 20, ok: true,
+		},
+		{
+			name:      "parent before category",
+			redirects: db.Redirects{"11": 21, "12": 22},
+			from:      11, to: 21, ok: true,
+		},
+		{
+			name:      "invalid target is skipped",
+			redirects: db.Redirects{"10": discord.NullChannelID, "12": 22},
+			from:      12, to: 22, ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := redirectFor(tt.redirects, ancestry)
+			if from != tt.from || to != tt.to || ok != tt.ok {
+				t.Errorf("redirectFor() = %v, %v, %v, want %v, %v, %v", from, to, ok, tt.from, tt.to, tt.ok)
+			}
+		})
+	}
+}
@@ -31,6 +31,21 @@ func (bot *Bot) GuildUser(guildID discord.GuildID, userID discord.UserID) (*disc
 	return u, nil
 }
 
+// CachedGuildUser returns a user from the member cache or the user cache, without making any API requests.
+// This should be used when many users are looked up at once, such as in lists.
+func (bot *Bot) CachedGuildUser(guildID discord.GuildID, userID discord.UserID) (*discord.User, bool) {
+	m, err := bot.Cabinet.Member(context.Background(), guildID, userID)
+	if err == nil {
+		return &m.User, true
+	}
+
+	bot.usersMu.Lock()
+	defer bot.usersMu.Unlock()
+
+	u, ok := bot.users[userID]
+	return u, ok
+}
+
 // User returns a user from the cache, or from Discord's API if the user is not cached.
 // NOTE: This method should be used very sparingly! If a guild ID is available, GuildUser should always be used instead.
 func (bot *Bot) User(userID discord.UserID) (*discord.User, error) {
@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v5"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// WatchlistField returns an embed field highlighting that the user is on the guild's watchlist.
+// ok is false if the user isn't on the watchlist.
+func (bot *Bot) WatchlistField(guildID discord.GuildID, userID discord.UserID) (field discord.EmbedField, ok bool) {
+	e, err := bot.DB.WatchlistUser(guildID, userID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Errorf("getting watchlist entry for %v in %v: %v", userID, guildID, err)
+		}
+		return field, false
+	}
+
+	moderator := e.Moderator.Mention()
+	if u, err := bot.GuildUser(guildID, e.Moderator); err == nil {
+		moderator = fmt.Sprintf("%v (%v)", u.Mention(), u.Tag())
+	}
+
+	return discord.EmbedField{
+		Name:  "⚠️ User is on the watchlist",
+		Value: fmt.Sprintf("**Reason:** %v\n**Added by:** %v\n**Added:** <t:%v>", e.Reason, moderator, e.Added.Unix()),
+	}, true
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/diamondburned/arikawa/v3/api/webhook"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mediocregopher/radix/v4"
+	"github.com/starshine-sys/catalogger/v2/common"
 	"github.com/starshine-sys/catalogger/v2/common/log"
 )
 
@@ -34,33 +35,42 @@ const (
 
 func webhookKey(id discord.ChannelID) string { return "webhook:" + id.String() }
 
-func (bot *Bot) fetchCachedWebhookKey(channelID discord.ChannelID) (id discord.WebhookID, token string, err error) {
+// fetchCachedWebhookKey returns the cached webhook for the given log channel.
+// If the log channel is a thread, the webhook belongs to its parent, and threadID is set to the log channel.
+func (bot *Bot) fetchCachedWebhookKey(channelID discord.ChannelID) (id discord.WebhookID, token string, threadID discord.ChannelID, err error) {
 	var s string
 	err = bot.DB.Redis.Do(context.Background(), radix.Cmd(&s, "GET", webhookKey(channelID)))
 	if err != nil {
 		return
 	}
 	if s == "" {
-		return id, token, errWebhookNotFound
+		return id, token, threadID, errWebhookNotFound
 	}
 
-	w := strings.SplitN(s, ":", 2)
-	if len(w) != 2 {
+	// stored as "id:token" for channels, and "id:token:thread" for threads
+	w := strings.SplitN(s, ":", 3)
+	if len(w) < 2 || (len(w) == 3 && w[2] != "thread") {
 		err = bot.DB.Redis.Do(context.Background(), radix.Cmd(nil, "DEL", webhookKey(channelID)))
-		return id, token, errors.Append(err, errWebhookInvalid)
+		return id, token, threadID, errors.Append(err, errWebhookInvalid)
 	}
 
 	whID, err := discord.ParseSnowflake(w[0])
 	if err != nil {
 		err = bot.DB.Redis.Do(context.Background(), radix.Cmd(nil, "DEL", webhookKey(channelID)))
-		return id, token, errors.Append(err, errWebhookInvalid)
+		return id, token, threadID, errors.Append(err, errWebhookInvalid)
 	}
 
-	return discord.WebhookID(whID), w[1], nil
+	if len(w) == 3 {
+		threadID = channelID
+	}
+	return discord.WebhookID(whID), w[1], threadID, nil
 }
 
-func (bot *Bot) storeWebhook(channelID discord.ChannelID, wh *discord.Webhook) (err error) {
+func (bot *Bot) storeWebhook(channelID discord.ChannelID, wh *discord.Webhook, isThread bool) (err error) {
 	stored := wh.ID.String() + ":" + wh.Token
+	if isThread {
+		stored += ":thread"
+	}
 
 	err = bot.DB.Redis.Do(context.Background(), radix.Cmd(nil, "SET", webhookKey(channelID), stored))
 	if err != nil {
@@ -69,20 +79,57 @@ func (bot *Bot) storeWebhook(channelID discord.ChannelID, wh *discord.Webhook) (
 	return nil
 }
 
-func (bot *Bot) getWebhook(channelID discord.ChannelID) (*discord.Webhook, error) {
+// getWebhook returns the webhook used to log to the given channel.
+// Threads and forum posts can't have webhooks of their own, so for those, the parent channel's webhook is returned,
+// and threadID is the ID that has to be passed when executing it.
+func (bot *Bot) getWebhook(channelID discord.ChannelID) (wh *discord.Webhook, threadID discord.ChannelID, err error) {
 	// check if we've got a cached webhook
-	id, token, err := bot.fetchCachedWebhookKey(channelID)
+	id, token, threadID, err := bot.fetchCachedWebhookKey(channelID)
 	if err == nil {
+		whChannelID := channelID
+		if threadID.IsValid() {
+			whChannelID = discord.NullChannelID
+		}
+
 		return &discord.Webhook{
 			ID:        id,
 			Token:     token,
-			ChannelID: channelID,
-		}, nil
+			ChannelID: whChannelID,
+		}, threadID, nil
 	}
 
 	log.Debugf("no cached webhook found for %v, falling back to API", channelID)
 
-	// no cached webhook, fetch webhooks instead
+	// threads use their parent channel's webhook
+	parentID := channelID
+	ch, err := bot.Cabinet.Channel(context.Background(), channelID)
+	if err != nil {
+		chp, err := bot.Router.Rest.Channel(channelID)
+		if err != nil {
+			return nil, threadID, errors.Wrap(err, "getting channel")
+		}
+		ch = *chp
+	}
+	if common.IsThread(ch) {
+		parentID = ch.ParentID
+		threadID = channelID
+	}
+
+	wh, err = bot.parentWebhook(parentID)
+	if err != nil {
+		return nil, threadID, err
+	}
+
+	err = bot.storeWebhook(channelID, wh, threadID.IsValid())
+	if err != nil {
+		log.Errorf("storing webhook %v in %v: %v", wh.ID, channelID, err)
+	}
+	return wh, threadID, nil
+}
+
+// parentWebhook fetches or creates the bot's webhook in the given (non-thread) channel.
+func (bot *Bot) parentWebhook(channelID discord.ChannelID) (*discord.Webhook, error) {
+	// fetch webhooks
 	whs, err := bot.Router.Rest.ChannelWebhooks(channelID)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting webhooks")
@@ -91,10 +138,6 @@ func (bot *Bot) getWebhook(channelID discord.ChannelID) (*discord.Webhook, error
 	// return first webhook where user ID == our ID
 	for _, wh := range whs {
 		if wh.User != nil && wh.User.ID == bot.user.ID {
-			err = bot.storeWebhook(channelID, &wh)
-			if err != nil {
-				log.Errorf("storing webhook %v in %v: %v", wh.ID, channelID, err)
-			}
 			return &wh, nil
 		}
 	}
@@ -108,11 +151,5 @@ func (bot *Bot) getWebhook(channelID discord.ChannelID) (*discord.Webhook, error
 	if err != nil {
 		return nil, errors.Wrap(err, "creating new webhook")
 	}
-
-	err = bot.storeWebhook(channelID, wh)
-	if err != nil {
-		log.Errorf("storing webhook %v in %v: %v", wh.ID, channelID, err)
-	}
-
 	return wh, nil
 }
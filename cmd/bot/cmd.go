@@ -3,17 +3,22 @@ package bot
 import (
 	"os"
 	"os/signal"
+	"syscall"
 
 	"emperror.dev/errors"
 	"github.com/getsentry/sentry-go"
 	"github.com/starshine-sys/catalogger/v2/bot"
 	"github.com/starshine-sys/catalogger/v2/commands/config"
+	invitecommands "github.com/starshine-sys/catalogger/v2/commands/invites"
+	messagecommands "github.com/starshine-sys/catalogger/v2/commands/messages"
 	metacommands "github.com/starshine-sys/catalogger/v2/commands/meta"
+	"github.com/starshine-sys/catalogger/v2/commands/watchlist"
 	"github.com/starshine-sys/catalogger/v2/common"
 	"github.com/starshine-sys/catalogger/v2/common/log"
 	"github.com/starshine-sys/catalogger/v2/logging/cache"
 	"github.com/starshine-sys/catalogger/v2/logging/channels"
 	"github.com/starshine-sys/catalogger/v2/logging/invites"
+	"github.com/starshine-sys/catalogger/v2/logging/members"
 	"github.com/starshine-sys/catalogger/v2/logging/messages"
 	"github.com/starshine-sys/catalogger/v2/logging/meta"
 	"github.com/starshine-sys/catalogger/v2/logging/roles"
@@ -60,12 +65,16 @@ func run(c *cli.Context) error {
 	meta.Setup(b)     // meta logging (guilds, ready)
 	invites.Setup(b)  // invite logging
 	channels.Setup(b) // channel logging
+	members.Setup(b)  // member logging (joins, leaves, bans)
 
-	config.Setup(b)       // config commands
-	metacommands.Setup(b) // meta commands
+	config.Setup(b)          // config commands
+	metacommands.Setup(b)    // meta commands
+	messagecommands.Setup(b) // message history commands
+	watchlist.Setup(b)       // watchlist commands
+	invitecommands.Setup(b)  // invite commands
 
 	// actually run bot!
-	ctx, cancel := signal.NotifyContext(c.Context, os.Interrupt, os.Kill)
+	ctx, cancel := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	err = b.Open(ctx)
@@ -81,5 +90,10 @@ func run(c *cli.Context) error {
 	}()
 
 	<-ctx.Done()
+
+	// send any queued embeds before shutting down, so they aren't only sent after the next restart
+	log.Info("shutting down, flushing embed queues")
+	b.FlushQueues()
+	b.FlushDeliveries()
 	return nil
 }
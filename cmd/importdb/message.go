@@ -114,11 +114,19 @@ func InsertNewMessage(conn *pgx.Conn, key [32]byte, oldMessage OldMessage) (err
 		Member:   oldMessage.Member,
 		System:   oldMessage.System,
 
-		Metadata: (*NewMetadata)(oldMessage.Metadata),
-
 		AttachmentSize: 0,
 	}
 
+	// the old metadata only has a subset of the new fields, so it can't be converted directly
+	if md := oldMessage.Metadata; md != nil {
+		m.Metadata = &NewMetadata{
+			UserID:   md.UserID,
+			Username: md.Username,
+			Avatar:   md.Avatar,
+			Embeds:   md.Embeds,
+		}
+	}
+
 	if m.Content == "" {
 		m.Content = "None"
 	}
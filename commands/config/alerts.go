@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func (bot *Bot) alertsSet(ctx *bcr.CommandContext) (err error) {
+	opts := bot.Options(ctx)
+	event := opts.Find("event").String()
+
+	roles, err := bot.DB.AlertRoles(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting alert roles for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting alert roles"))
+	}
+	if roles == nil {
+		roles = db.AlertRoles{}
+	}
+
+	// if no role is given, remove the event's alert role
+	sf, err := opts.Find("role").SnowflakeValue()
+	if err != nil || !sf.IsValid() {
+		if _, ok := roles[event]; !ok {
+			return ctx.ReplyEphemeral(fmt.Sprintf("**%v** doesn't ping a role.", common.EventName(event)))
+		}
+		delete(roles, event)
+	} else {
+		roles[event] = discord.RoleID(sf)
+	}
+
+	err = bot.DB.SetAlertRoles(ctx.Event.GuildID, roles)
+	if err != nil {
+		log.Errorf("setting alert roles for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "setting alert roles"))
+	}
+
+	bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+	if id, ok := roles[event]; ok {
+		return ctx.ReplyEphemeral(fmt.Sprintf("%v will now be pinged when **%v** is logged.", id.Mention(), common.EventName(event)))
+	}
+	return ctx.ReplyEphemeral(fmt.Sprintf("**%v** will no longer ping a role.", common.EventName(event)))
+}
+
+func (bot *Bot) alertsList(ctx *bcr.CommandContext) (err error) {
+	roles, err := bot.DB.AlertRoles(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting alert roles for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting alert roles"))
+	}
+
+	if len(roles) == 0 {
+		return ctx.ReplyEphemeral("No events ping a role in this server.")
+	}
+
+	var lines []string
+	for event, id := range roles {
+		lines = append(lines, fmt.Sprintf("**%v**: %v", common.EventName(event), id.Mention()))
+	}
+	sort.Strings(lines)
+
+	return ctx.ReplyEphemeral("", discord.Embed{
+		Title:       "Alert roles",
+		Description: strings.Join(lines, "\n"),
+		Color:       common.ColourPurple,
+	})
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+var systemIDRegex = regexp.MustCompile(`^[a-z]{5,6}$`)
+
+// systemID returns the system ID given in the "system" option, or false if it's invalid.
+// PluralKit sometimes shows IDs split with a hyphen, so that's removed.
+func (bot *Bot) systemID(ctx *bcr.CommandContext) (string, bool) {
+	id := bot.Options(ctx).Find("system").String()
+	id = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(id), "-", ""))
+	return id, systemIDRegex.MatchString(id)
+}
+
+func (bot *Bot) bannedSystemsAdd(ctx *bcr.CommandContext) (err error) {
+	id, ok := bot.systemID(ctx)
+	if !ok {
+		return ctx.ReplyEphemeral("That isn't a valid PluralKit system ID.")
+	}
+
+	added, err := bot.DB.AddBannedSystem(ctx.Event.GuildID, id)
+	if err != nil {
+		log.Errorf("adding banned system %v in guild %v: %v", id, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "adding banned system"))
+	}
+
+	if !added {
+		return ctx.ReplyEphemeral(fmt.Sprintf("The system **%v** is already banned.", id))
+	}
+	return ctx.ReplyEphemeral(fmt.Sprintf("Banned the system **%v**. Messages by this system will now be logged to the banned systems log channel.", id))
+}
+
+func (bot *Bot) bannedSystemsRemove(ctx *bcr.CommandContext) (err error) {
+	id, ok := bot.systemID(ctx)
+	if !ok {
+		return ctx.ReplyEphemeral("That isn't a valid PluralKit system ID.")
+	}
+
+	removed, err := bot.DB.RemoveBannedSystem(ctx.Event.GuildID, id)
+	if err != nil {
+		log.Errorf("removing banned system %v in guild %v: %v", id, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "removing banned system"))
+	}
+
+	if !removed {
+		return ctx.ReplyEphemeral(fmt.Sprintf("The system **%v** isn't banned.", id))
+	}
+	return ctx.ReplyEphemeral(fmt.Sprintf("Unbanned the system **%v**.", id))
+}
+
+func (bot *Bot) bannedSystemsList(ctx *bcr.CommandContext) (err error) {
+	systems, err := bot.DB.BannedSystems(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting banned systems in guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting banned systems"))
+	}
+
+	if len(systems) == 0 {
+		return ctx.ReplyEphemeral("There are no banned systems in this server.")
+	}
+
+	desc := "`" + strings.Join(systems, "`, `") + "`"
+	if len(desc) > 4000 {
+		desc = desc[:4000] + "…"
+	}
+
+	return ctx.ReplyEphemeral("", discord.Embed{
+		Title:       fmt.Sprintf("Banned systems (%v)", len(systems)),
+		Description: desc,
+		Color:       common.ColourPurple,
+	})
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func (bot *Bot) batchingSet(ctx *bcr.CommandContext) (err error) {
+	opts := bot.Options(ctx)
+	event := opts.Find("event").String()
+
+	policies, err := bot.DB.QueuePolicies(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting batching policies for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting batching policies"))
+	}
+	if policies == nil {
+		policies = db.QueuePolicies{}
+	}
+	p := policies[event]
+
+	if opt := opts.Find("enabled"); opt.Name != "" {
+		enabled, err := opt.BoolValue()
+		if err != nil {
+			return ctx.ReplyEphemeral("That isn't a valid value for enabled.")
+		}
+		p.Enabled = &enabled
+	}
+	if opt := opts.Find("max-embeds"); opt.Name != "" {
+		n, err := opt.IntValue()
+		if err != nil || n < 1 || n > maxBatchEmbeds {
+			return ctx.ReplyEphemeral(fmt.Sprintf("The maximum number of embeds must be between 1 and %v.", maxBatchEmbeds))
+		}
+		p.MaxEmbeds = int(n)
+	}
+	if opt := opts.Find("max-delay"); opt.Name != "" {
+		seconds, err := opt.IntValue()
+		if err != nil || seconds < 1 || time.Duration(seconds)*time.Second > maxBatchDelay {
+			return ctx.ReplyEphemeral(fmt.Sprintf("The delay must be between 1 and %v seconds.", int(maxBatchDelay/time.Second)))
+		}
+		p.MaxDelay = time.Duration(seconds) * time.Second
+	}
+	if opt := opts.Find("max-length"); opt.Name != "" {
+		n, err := opt.IntValue()
+		if err != nil || n < 1 || n > maxBatchLength {
+			return ctx.ReplyEphemeral(fmt.Sprintf("The maximum length must be between 1 and %v.", maxBatchLength))
+		}
+		p.MaxLength = int(n)
+	}
+
+	if p.IsEmpty() {
+		return ctx.ReplyEphemeral("You must give at least one setting to change.")
+	}
+	policies[event] = p
+
+	err = bot.DB.SetQueuePolicies(ctx.Event.GuildID, policies)
+	if err != nil {
+		log.Errorf("setting batching policies for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "setting batching policies"))
+	}
+
+	bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("Batching for **%v** is now: %v", common.EventName(event), policyString(p)))
+}
+
+func (bot *Bot) batchingReset(ctx *bcr.CommandContext) (err error) {
+	event := bot.Options(ctx).Find("event").String()
+
+	policies, err := bot.DB.QueuePolicies(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting batching policies for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting batching policies"))
+	}
+
+	if _, ok := policies[event]; !ok {
+		return ctx.ReplyEphemeral(fmt.Sprintf("**%v** already uses the default batching.", common.EventName(event)))
+	}
+	delete(policies, event)
+
+	err = bot.DB.SetQueuePolicies(ctx.Event.GuildID, policies)
+	if err != nil {
+		log.Errorf("setting batching policies for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "setting batching policies"))
+	}
+
+	bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("**%v** now uses the default batching.", common.EventName(event)))
+}
+
+func (bot *Bot) batchingList(ctx *bcr.CommandContext) (err error) {
+	policies, err := bot.DB.QueuePolicies(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting batching policies for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting batching policies"))
+	}
+
+	if len(policies) == 0 {
+		return ctx.ReplyEphemeral("This server uses the default batching for all events.")
+	}
+
+	var lines []string
+	for event, p := range policies {
+		lines = append(lines, fmt.Sprintf("**%v**: %v", common.EventName(event), policyString(p)))
+	}
+	sort.Strings(lines)
+
+	return ctx.ReplyEphemeral("", discord.Embed{
+		Title:       "Batching",
+		Description: strings.Join(lines, "\n"),
+		Color:       common.ColourPurple,
+		Footer: &discord.EmbedFooter{
+			Text: "Settings that aren't shown use the default.",
+		},
+	})
+}
+
+// policyString returns a readable description of the fields set in a batching policy.
+func policyString(p db.QueuePolicy) string {
+	var s []string
+	if p.Enabled != nil {
+		if *p.Enabled {
+			s = append(s, "enabled")
+		} else {
+			s = append(s, "disabled")
+		}
+	}
+	if p.MaxEmbeds != 0 {
+		s = append(s, fmt.Sprintf("up to %v embeds", p.MaxEmbeds))
+	}
+	if p.MaxDelay != 0 {
+		s = append(s, fmt.Sprintf("sent after %v", p.MaxDelay))
+	}
+	if p.MaxLength != 0 {
+		s = append(s, fmt.Sprintf("up to %v characters", p.MaxLength))
+	}
+	return strings.Join(s, ", ")
+}
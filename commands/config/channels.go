@@ -15,133 +15,30 @@ import (
 	"github.com/starshine-sys/catalogger/v2/common/log"
 )
 
-var channelButtons = discord.ContainerComponents{
-	&discord.ActionRowComponent{
-		&discord.ButtonComponent{
-			Label:    "Server changes",
-			CustomID: "channel:GUILD_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Emote changes",
-			CustomID: "channel:GUILD_EMOJIS_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "New roles",
-			CustomID: "channel:GUILD_ROLE_CREATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Edited roles",
-			CustomID: "channel:GUILD_ROLE_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Deleted roles",
-			CustomID: "channel:GUILD_ROLE_DELETE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-	},
-	&discord.ActionRowComponent{
-		&discord.ButtonComponent{
-			Label:    "New channels",
-			CustomID: "channel:CHANNEL_CREATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Edited channels",
-			CustomID: "channel:CHANNEL_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Deleted channels",
-			CustomID: "channel:CHANNEL_DELETE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Members joining",
-			CustomID: "channel:GUILD_MEMBER_ADD",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Members leaving",
-			CustomID: "channel:GUILD_MEMBER_REMOVE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-	},
-	&discord.ActionRowComponent{
-		&discord.ButtonComponent{
-			Label:    "Member role changes",
-			CustomID: "channel:GUILD_MEMBER_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Key role changes",
-			CustomID: "channel:GUILD_KEY_ROLE_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Member name changes",
-			CustomID: "channel:GUILD_MEMBER_NICK_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Avatar changes",
-			CustomID: "channel:GUILD_MEMBER_AVATAR_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Kicks",
-			CustomID: "channel:GUILD_MEMBER_KICK",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-	},
-	&discord.ActionRowComponent{
-		&discord.ButtonComponent{
-			Label:    "Bans",
-			CustomID: "channel:GUILD_BAN_ADD",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Unbans",
-			CustomID: "channel:GUILD_BAN_REMOVE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "New invites",
-			CustomID: "channel:INVITE_CREATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Deleted invites",
-			CustomID: "channel:INVITE_DELETE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Edited messages",
-			CustomID: "channel:MESSAGE_UPDATE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-	},
-	&discord.ActionRowComponent{
-		&discord.ButtonComponent{
-			Label:    "Deleted messages",
-			CustomID: "channel:MESSAGE_DELETE",
-			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Bulk deleted messages",
-			CustomID: "channel:MESSAGE_DELETE_BULK",
+// channelButtons has a button for every event, five per row, followed by a close button.
+var channelButtons = func() (rows discord.ContainerComponents) {
+	var row discord.ActionRowComponent
+	for _, ev := range common.LogEvents {
+		if len(row) == 5 {
+			full := row
+			rows = append(rows, &full)
+			row = discord.ActionRowComponent{}
+		}
+
+		row = append(row, &discord.ButtonComponent{
+			Label:    ev.Title,
+			CustomID: discord.ComponentID("channel:" + ev.Key),
 			Style:    discord.PrimaryButtonStyle(),
-		},
-		&discord.ButtonComponent{
-			Label:    "Close",
-			CustomID: "channel:close",
-			Style:    discord.SecondaryButtonStyle(),
-		},
-	},
-}
+		})
+	}
+
+	row = append(row, &discord.ButtonComponent{
+		Label:    "Close",
+		CustomID: "channel:close",
+		Style:    discord.SecondaryButtonStyle(),
+	})
+	return append(rows, &row)
+}()
 
 func (bot *Bot) channelsEntry(ctx *bcr.CommandContext) (err error) {
 	logChannels, err := bot.DB.Channels(ctx.Event.GuildID)
@@ -174,39 +71,34 @@ func (bot *Bot) channelsEntry(ctx *bcr.CommandContext) (err error) {
 		return id.Mention()
 	}
 
+	// destinationsString returns the event's main log channel, followed by any extra channels
+	destinationsString := func(key string, id discord.ChannelID) string {
+		s := prettyChannelString(id)
+		if !id.IsValid() {
+			return s
+		}
+
+		for _, extra := range logChannels.Channels.Extra[key] {
+			s += "\n+ " + prettyChannelString(extra)
+		}
+		return s
+	}
+
 	embed := func() discord.Embed {
+		fields := make([]discord.EmbedField, 0, len(common.LogEvents))
+		for _, ev := range common.LogEvents {
+			fields = append(fields, discord.EmbedField{
+				Name:   ev.Title,
+				Value:  destinationsString(ev.Key, logChannels.Channels.ForKey(ev.Key)),
+				Inline: true,
+			})
+		}
+
 		return discord.Embed{
 			Title:       "Log channels for " + ctx.Guild.Name,
-			Description: "Click one of the buttons below to change the channel for that event.",
+			Description: "Click one of the buttons below to change the channels for that event.\nChannels marked with + are extra channels the event is also logged to.",
 			Color:       common.ColourPurple,
-			Fields: []discord.EmbedField{
-				{Name: "Server changes", Value: prettyChannelString(logChannels.Channels.GuildUpdate), Inline: true},
-				{Name: "Emote changes", Value: prettyChannelString(logChannels.Channels.GuildEmojisUpdate), Inline: true},
-				{Name: "New roles", Value: prettyChannelString(logChannels.Channels.GuildRoleCreate), Inline: true},
-				{Name: "Edited roles", Value: prettyChannelString(logChannels.Channels.GuildRoleUpdate), Inline: true},
-				{Name: "Deleted roles", Value: prettyChannelString(logChannels.Channels.GuildRoleDelete), Inline: true},
-
-				{Name: "New channels", Value: prettyChannelString(logChannels.Channels.ChannelCreate), Inline: true},
-				{Name: "Edited channels", Value: prettyChannelString(logChannels.Channels.ChannelUpdate), Inline: true},
-				{Name: "Deleted channels", Value: prettyChannelString(logChannels.Channels.ChannelDelete), Inline: true},
-				{Name: "Members joining", Value: prettyChannelString(logChannels.Channels.GuildMemberAdd), Inline: true},
-				{Name: "Members leaving", Value: prettyChannelString(logChannels.Channels.GuildMemberRemove), Inline: true},
-
-				{Name: "Member role changes", Value: prettyChannelString(logChannels.Channels.GuildMemberUpdate), Inline: true},
-				{Name: "Key role changes", Value: prettyChannelString(logChannels.Channels.GuildKeyRoleUpdate), Inline: true},
-				{Name: "Member name changes", Value: prettyChannelString(logChannels.Channels.GuildMemberNickUpdate), Inline: true},
-				{Name: "Avatar changes", Value: prettyChannelString(logChannels.Channels.GuildMemberAvatarUpdate), Inline: true},
-				{Name: "Kicks", Value: prettyChannelString(logChannels.Channels.GuildMemberKick), Inline: true},
-
-				{Name: "Bans", Value: prettyChannelString(logChannels.Channels.GuildBanAdd), Inline: true},
-				{Name: "Unbans", Value: prettyChannelString(logChannels.Channels.GuildBanRemove), Inline: true},
-				{Name: "New invites", Value: prettyChannelString(logChannels.Channels.InviteCreate), Inline: true},
-				{Name: "Deleted invites", Value: prettyChannelString(logChannels.Channels.InviteDelete), Inline: true},
-				{Name: "Edited messages", Value: prettyChannelString(logChannels.Channels.MessageUpdate), Inline: true},
-
-				{Name: "Deleted messages", Value: prettyChannelString(logChannels.Channels.MessageDelete), Inline: true},
-				{Name: "Bulk deleted messages", Value: prettyChannelString(logChannels.Channels.MessageDeleteBulk), Inline: true},
-			},
+			Fields:      fields,
 		}
 	}
 
@@ -232,6 +124,10 @@ func (bot *Bot) channelsEntry(ctx *bcr.CommandContext) (err error) {
 		return err
 	}
 
+	if logChannels.Channels.Extra == nil {
+		logChannels.Channels.Extra = map[string][]discord.ChannelID{}
+	}
+
 	// timeout after 10 minutes
 	cctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
@@ -281,54 +177,11 @@ func (bot *Bot) channelsEntry(ctx *bcr.CommandContext) (err error) {
 				continue
 			}
 
-			var hctx bcr.HasContext
-			// yes, this has to be done manually, no easy tricks to reduce code here
-			switch bctx.CustomID {
-			case "channel:GUILD_UPDATE":
-				hctx = bot.channelPage(bctx, "Server changes", &logChannels.Channels.GuildUpdate, prettyChannelString)
-			case "channel:GUILD_EMOJIS_UPDATE":
-				hctx = bot.channelPage(bctx, "Emote changes", &logChannels.Channels.GuildEmojisUpdate, prettyChannelString)
-			case "channel:GUILD_ROLE_CREATE":
-				hctx = bot.channelPage(bctx, "New roles", &logChannels.Channels.GuildRoleCreate, prettyChannelString)
-			case "channel:GUILD_ROLE_UPDATE":
-				hctx = bot.channelPage(bctx, "Edited roles", &logChannels.Channels.GuildRoleUpdate, prettyChannelString)
-			case "channel:GUILD_ROLE_DELETE":
-				hctx = bot.channelPage(bctx, "Deleted roles", &logChannels.Channels.GuildRoleDelete, prettyChannelString)
-			case "channel:CHANNEL_CREATE":
-				hctx = bot.channelPage(bctx, "New channels", &logChannels.Channels.ChannelCreate, prettyChannelString)
-			case "channel:CHANNEL_UPDATE":
-				hctx = bot.channelPage(bctx, "Edited channels", &logChannels.Channels.ChannelUpdate, prettyChannelString)
-			case "channel:CHANNEL_DELETE":
-				hctx = bot.channelPage(bctx, "Deleted channels", &logChannels.Channels.ChannelDelete, prettyChannelString)
-			case "channel:GUILD_MEMBER_ADD":
-				hctx = bot.channelPage(bctx, "Members joining", &logChannels.Channels.GuildMemberAdd, prettyChannelString)
-			case "channel:GUILD_MEMBER_REMOVE":
-				hctx = bot.channelPage(bctx, "Members leaving", &logChannels.Channels.GuildMemberRemove, prettyChannelString)
-			case "channel:GUILD_MEMBER_UPDATE":
-				hctx = bot.channelPage(bctx, "Member role changes", &logChannels.Channels.GuildMemberUpdate, prettyChannelString)
-			case "channel:GUILD_KEY_ROLE_UPDATE":
-				hctx = bot.channelPage(bctx, "Key role changes", &logChannels.Channels.GuildKeyRoleUpdate, prettyChannelString)
-			case "channel:GUILD_MEMBER_NICK_UPDATE":
-				hctx = bot.channelPage(bctx, "Member name changes", &logChannels.Channels.GuildMemberNickUpdate, prettyChannelString)
-			case "channel:GUILD_MEMBER_AVATAR_UPDATE":
-				hctx = bot.channelPage(bctx, "Avatar changes", &logChannels.Channels.GuildMemberAvatarUpdate, prettyChannelString)
-			case "channel:GUILD_MEMBER_KICK":
-				hctx = bot.channelPage(bctx, "Kicks", &logChannels.Channels.GuildMemberKick, prettyChannelString)
-			case "channel:GUILD_BAN_ADD":
-				hctx = bot.channelPage(bctx, "Bans", &logChannels.Channels.GuildBanAdd, prettyChannelString)
-			case "channel:GUILD_BAN_REMOVE":
-				hctx = bot.channelPage(bctx, "Unbans", &logChannels.Channels.GuildBanRemove, prettyChannelString)
-			case "channel:INVITE_CREATE":
-				hctx = bot.channelPage(bctx, "New invites", &logChannels.Channels.InviteCreate, prettyChannelString)
-			case "channel:INVITE_DELETE":
-				hctx = bot.channelPage(bctx, "Deleted invites", &logChannels.Channels.InviteDelete, prettyChannelString)
-			case "channel:MESSAGE_UPDATE":
-				hctx = bot.channelPage(bctx, "Edited messages", &logChannels.Channels.MessageUpdate, prettyChannelString)
-			case "channel:MESSAGE_DELETE":
-				hctx = bot.channelPage(bctx, "Deleted messages", &logChannels.Channels.MessageDelete, prettyChannelString)
-			case "channel:MESSAGE_DELETE_BULK":
-				hctx = bot.channelPage(bctx, "Bulk deleted messages", &logChannels.Channels.MessageDeleteBulk, prettyChannelString)
+			logEvent, ok := common.EventByKey(strings.TrimPrefix(string(bctx.CustomID), "channel:"))
+			if !ok {
+				continue
 			}
+			hctx := bot.channelPage(bctx, logEvent.Title, logChannels.Channels.Field(logEvent.Key), logChannels.Channels.Extra, prettyChannelString)
 
 			// the previous function *probably* updated something, but it's easier to just *always* update the db
 			err = bot.DB.SetChannels(ev.GuildID, logChannels)
@@ -353,7 +206,9 @@ func (bot *Bot) channelsEntry(ctx *bcr.CommandContext) (err error) {
 	}
 }
 
-func (bot *Bot) channelPage(ctx *bcr.ButtonContext, subject string, channelID *discord.ChannelID, toString func(id discord.ChannelID) string) (returnCtx bcr.HasContext) {
+func (bot *Bot) channelPage(ctx *bcr.ButtonContext, subject string, channelID *discord.ChannelID, extra map[string][]discord.ChannelID, toString func(id discord.ChannelID) string) (returnCtx bcr.HasContext) {
+	key := strings.TrimPrefix(string(ctx.CustomID), "channel:")
+
 	var desc string
 	if !channelID.IsValid() {
 		desc = "This event is not currently logged.\nTo start logging it somewhere, select a channel below."
@@ -361,7 +216,47 @@ func (bot *Bot) channelPage(ctx *bcr.ButtonContext, subject string, channelID *d
 		desc = fmt.Sprintf(`This event is currently set to log to %v.
 To change where it is logged, select a channel below.
 To disable logging this event entirely, select "Stop logging" below.`, toString(*channelID))
+
+		if len(extra[key]) > 0 {
+			var extras []string
+			for _, id := range extra[key] {
+				extras = append(extras, toString(id))
+			}
+			desc += "\n\nIt is also logged to " + strings.Join(extras, ", ") + "."
+		}
+		desc += "\nTo also log it to another channel, or to stop logging it to an extra channel, select that channel in the second menu."
+	}
+
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ChannelSelectComponent{
+				CustomID:     "channel:select",
+				ChannelTypes: common.LogChannelTypes,
+				Placeholder:  "Main log channel",
+			},
+		},
 	}
+	if channelID.IsValid() {
+		components = append(components, &discord.ActionRowComponent{
+			&discord.ChannelSelectComponent{
+				CustomID:     "channel:extra",
+				ChannelTypes: common.LogChannelTypes,
+				Placeholder:  "Add or remove an extra log channel",
+			},
+		})
+	}
+	components = append(components, &discord.ActionRowComponent{
+		&discord.ButtonComponent{
+			CustomID: "channel:reset",
+			Label:    "Stop logging",
+			Style:    discord.DangerButtonStyle(),
+		},
+		&discord.ButtonComponent{
+			CustomID: "channel:cancel",
+			Label:    "Return to menu",
+			Style:    discord.SecondaryButtonStyle(),
+		},
+	})
 
 	err := ctx.State.RespondInteraction(ctx.InteractionID, ctx.InteractionToken, api.InteractionResponse{
 		Type: api.UpdateMessage,
@@ -371,26 +266,7 @@ To disable logging this event entirely, select "Stop logging" below.`, toString(
 				Description: desc,
 				Color:       common.ColourPurple,
 			}},
-			Components: &discord.ContainerComponents{
-				&discord.ActionRowComponent{
-					&discord.ChannelSelectComponent{
-						CustomID:     "channel:select",
-						ChannelTypes: []discord.ChannelType{discord.GuildText},
-					},
-				},
-				&discord.ActionRowComponent{
-					&discord.ButtonComponent{
-						CustomID: "channel:reset",
-						Label:    "Stop logging",
-						Style:    discord.DangerButtonStyle(),
-					},
-					&discord.ButtonComponent{
-						CustomID: "channel:cancel",
-						Label:    "Return to menu",
-						Style:    discord.SecondaryButtonStyle(),
-					},
-				},
-			},
+			Components: &components,
 		},
 	})
 	if err != nil {
@@ -435,10 +311,24 @@ To disable logging this event entirely, select "Stop logging" below.`, toString(
 		if len(data.Values) == 0 {
 			return
 		}
+
+		if data.CustomID == "channel:extra" {
+			// the main log channel doesn't need to be an extra channel too
+			if data.Values[0] != *channelID {
+				extra[key], _ = common.Toggle(extra[key], data.Values[0])
+			}
+			return
+		}
+
 		*channelID = data.Values[0]
+		// if the new main log channel was an extra channel, remove it from the extra channels
+		if common.Contains(extra[key], *channelID) {
+			extra[key], _ = common.Toggle(extra[key], *channelID)
+		}
 	case *discord.ButtonInteraction:
 		if data.CustomID == "channel:reset" {
 			*channelID = discord.NullChannelID
+			delete(extra, key)
 		}
 	}
 
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+func (bot *Bot) dossiers(ctx *bcr.CommandContext) (err error) {
+	current, err := bot.DB.DossierChannel(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting dossier channel for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting setting"))
+	}
+
+	opts := bot.Options(ctx)
+
+	// if no value is given, show the current setting
+	if opts.Find("channel").Name == "" && opts.Find("disable").Name == "" {
+		if !current.IsValid() {
+			return ctx.ReplyEphemeral("Member dossiers are currently **disabled**.")
+		}
+		return ctx.ReplyEphemeral(fmt.Sprintf("Member dossiers are currently posted in %v.", current.Mention()))
+	}
+
+	var channelID discord.ChannelID
+	if disable, _ := opts.Find("disable").BoolValue(); !disable {
+		sf, err := opts.Find("channel").SnowflakeValue()
+		if err != nil || !sf.IsValid() {
+			return ctx.ReplyEphemeral("You must give a forum channel, or disable dossiers.")
+		}
+		channelID = discord.ChannelID(sf)
+
+		ch, err := bot.Cabinet.Channel(context.Background(), channelID)
+		if err != nil {
+			log.Errorf("getting channel %v: %v", channelID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "getting channel"))
+		}
+		if ch.GuildID != ctx.Event.GuildID || ch.Type != discord.GuildForum {
+			return ctx.ReplyEphemeral("The dossier channel must be a forum channel in this server.")
+		}
+	}
+
+	err = bot.DB.SetDossierChannel(ctx.Event.GuildID, channelID)
+	if err != nil {
+		log.Errorf("setting dossier channel for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "updating setting"))
+	}
+
+	bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+	// existing posts are in the old channel, so new ones have to be created
+	if channelID != current {
+		err = bot.DB.ClearDossierThreads(ctx.Event.GuildID)
+		if err != nil {
+			log.Errorf("clearing dossier threads for guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "clearing dossier threads"))
+		}
+	}
+
+	if !channelID.IsValid() {
+		return ctx.ReplyEphemeral("Member dossiers are now disabled.")
+	}
+	return ctx.ReplyEphemeral(fmt.Sprintf("Member dossiers will now be posted in %v. Each member gets their own post the first time an event concerning them is logged.", channelID.Mention()))
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+)
+
+func (bot *Bot) explain(ctx *bcr.CommandContext) (err error) {
+	opts := bot.Options(ctx)
+	key := opts.Find("event").String()
+
+	ev, ok := common.EventByKey(key)
+	if !ok {
+		return ctx.ReplyEphemeral("That isn't a valid event.")
+	}
+
+	var s EventSubject
+	if sf, err := opts.Find("channel").SnowflakeValue(); err == nil && sf.IsValid() {
+		s.ChannelID = discord.ChannelID(sf)
+	}
+	if sf, err := opts.Find("user").SnowflakeValue(); err == nil && sf.IsValid() {
+		s.UserID = discord.UserID(sf)
+	}
+
+	route := bot.Route(ctx.Event.GuildID, ev.Name, s)
+
+	var about []string
+	if s.ChannelID.IsValid() {
+		about = append(about, "in "+s.ChannelID.Mention())
+	}
+	if s.UserID.IsValid() {
+		about = append(about, "by "+s.UserID.Mention())
+	}
+
+	var mentions []string
+	for _, id := range route.ChannelIDs {
+		mentions = append(mentions, id.Mention())
+	}
+
+	desc := fmt.Sprintf("✅ This event is logged in %v, because %v.", strings.Join(mentions, ", "), route.Reason)
+	colour := common.ColourGreen
+	if route.Suppressed() {
+		desc = fmt.Sprintf("❌ This event is not logged, because %v.", route.Reason)
+		colour = common.ColourRed
+	}
+
+	var steps strings.Builder
+	for i, step := range route.Steps {
+		fmt.Fprintf(&steps, "%v. %v\n", i+1, step)
+	}
+
+	title := ev.Title
+	if len(about) > 0 {
+		title += " " + strings.Join(about, " ")
+	}
+
+	return ctx.ReplyEphemeral("", discord.Embed{
+		Title:       "Explanation",
+		Description: fmt.Sprintf("**%v**\n\n%v", title, desc),
+		Color:       colour,
+		Fields: []discord.EmbedField{{
+			Name:  "Checks",
+			Value: steps.String(),
+		}},
+	})
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// maxGhostPingWindow is the longest ghost ping window a guild can set.
+const maxGhostPingWindow = time.Hour
+
+func (bot *Bot) ghostPings(ctx *bcr.CommandContext) (err error) {
+	opt := bot.Options(ctx).Find("window")
+
+	// if no value is given, show the current setting
+	if opt.Name == "" {
+		window, err := bot.DB.GhostPingWindow(ctx.Event.GuildID)
+		if err != nil {
+			log.Errorf("getting ghost ping window for guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "getting setting"))
+		}
+
+		return ctx.ReplyEphemeral(fmt.Sprintf("Messages with pings that are deleted within **%v** of being sent are logged as ghost pings.", window))
+	}
+
+	seconds, err := opt.IntValue()
+	if err != nil || seconds < 1 || time.Duration(seconds)*time.Second > maxGhostPingWindow {
+		return ctx.ReplyEphemeral(fmt.Sprintf("The window must be between 1 and %v seconds.", int(maxGhostPingWindow/time.Second)))
+	}
+	window := time.Duration(seconds) * time.Second
+
+	err = bot.DB.SetGhostPingWindow(ctx.Event.GuildID, window)
+	if err != nil {
+		log.Errorf("setting ghost ping window for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "updating setting"))
+	}
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("Messages with pings that are deleted within **%v** of being sent will now be logged as ghost pings.", window))
+}
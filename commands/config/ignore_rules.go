@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// ignoreRulesPerPage is the number of rules shown per page in the rule list.
+const ignoreRulesPerPage = 20
+
+func (bot *Bot) ignoreRulesAdd(ctx *bcr.CommandContext) (err error) {
+	opts := bot.Options(ctx)
+
+	r := db.IgnoreRule{
+		GuildID: ctx.Event.GuildID,
+		Event:   opts.Find("event").String(),
+	}
+
+	var targets int
+	if sf, err := opts.Find("user").SnowflakeValue(); err == nil && sf.IsValid() {
+		id := discord.UserID(sf)
+		r.UserID = &id
+		targets++
+	}
+	if sf, err := opts.Find("channel").SnowflakeValue(); err == nil && sf.IsValid() {
+		id := discord.ChannelID(sf)
+		r.ChannelID = &id
+		targets++
+	}
+	if sf, err := opts.Find("role").SnowflakeValue(); err == nil && sf.IsValid() {
+		id := discord.RoleID(sf)
+		r.RoleID = &id
+		targets++
+	}
+
+	if targets != 1 {
+		return ctx.ReplyEphemeral("You must give exactly one of a user, channel, or role.")
+	}
+
+	id, err := bot.DB.AddIgnoreRule(r)
+	if err != nil {
+		log.Errorf("adding ignore rule in guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "adding ignore rule"))
+	}
+
+	bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+	}
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("Added ignore rule #%v: **%v** will no longer be logged for %v.",
+		id, common.EventName(r.Event), ruleTarget(guildChannels, r)))
+}
+
+func (bot *Bot) ignoreRulesRemove(ctx *bcr.CommandContext) (err error) {
+	id, err := bot.Options(ctx).Find("id").IntValue()
+	if err != nil {
+		return ctx.ReplyEphemeral("That isn't a valid rule ID.")
+	}
+
+	removed, err := bot.DB.RemoveIgnoreRule(ctx.Event.GuildID, int(id))
+	if err != nil {
+		log.Errorf("removing ignore rule %v in guild %v: %v", id, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "removing ignore rule"))
+	}
+
+	bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+	if !removed {
+		return ctx.ReplyEphemeral(fmt.Sprintf("There's no ignore rule with the ID #%v.", id))
+	}
+	return ctx.ReplyEphemeral(fmt.Sprintf("Removed ignore rule #%v.", id))
+}
+
+func (bot *Bot) ignoreRulesList(ctx *bcr.CommandContext) (err error) {
+	rules, err := bot.DB.IgnoreRules(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting ignore rules in guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting ignore rules"))
+	}
+
+	if len(rules) == 0 {
+		return ctx.ReplyEphemeral("There are no ignore rules in this server.")
+	}
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting guild channels"))
+	}
+
+	var lines []string
+	for _, r := range rules {
+		lines = append(lines, fmt.Sprintf("`#%v` **%v** for %v", r.ID, common.EventName(r.Event), ruleTarget(guildChannels, r)))
+	}
+
+	var embeds []discord.Embed
+	for i := 0; i < len(lines); i += ignoreRulesPerPage {
+		end := i + ignoreRulesPerPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		embeds = append(embeds, discord.Embed{
+			Title:       fmt.Sprintf("Ignore rules (%v)", len(rules)),
+			Description: strings.Join(lines[i:end], "\n"),
+			Color:       common.ColourPurple,
+		})
+	}
+
+	return bot.PaginateEmbeds(ctx, embeds)
+}
+
+// ruleTarget returns a readable description of what the rule applies to.
+func ruleTarget(chs []discord.Channel, r db.IgnoreRule) string {
+	switch {
+	case r.UserID != nil:
+		return "the user " + r.UserID.Mention()
+	case r.ChannelID != nil:
+		return "the channel " + channelString(chs, *r.ChannelID)
+	case r.RoleID != nil:
+		return "the role " + r.RoleID.Mention()
+	}
+	return "nothing"
+}
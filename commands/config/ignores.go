@@ -0,0 +1,425 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// ignoreChannelTypes are the channel types that can be ignored.
+var ignoreChannelTypes = []discord.ChannelType{
+	discord.GuildText, discord.GuildAnnouncement, discord.GuildCategory, discord.GuildForum,
+	discord.GuildPublicThread, discord.GuildPrivateThread, discord.GuildAnnouncementThread,
+}
+
+// ignoreMenu shows an ignore configuration menu.
+// update is called for every select menu interaction, until the menu is closed or times out after 10 minutes.
+func (bot *Bot) ignoreMenu(
+	ctx *bcr.CommandContext,
+	render func() (discord.Embed, discord.ContainerComponents),
+	update func(data discord.ComponentInteraction) error,
+) (err error) {
+	response := func() api.InteractionResponseData {
+		e, cs := render()
+		cs = append(cs, &discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				CustomID: "ignores:close",
+				Label:    "Close",
+				Style:    discord.SecondaryButtonStyle(),
+			},
+		})
+		return api.InteractionResponseData{
+			Embeds:     &[]discord.Embed{e},
+			Components: &cs,
+		}
+	}
+
+	err = ctx.ReplyComplex(response())
+	if err != nil {
+		log.Errorf("sending interaction response for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "sending initial embed"))
+	}
+
+	msg, err := ctx.Original()
+	if err != nil {
+		log.Errorf("getting original message for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting original message"))
+	}
+
+	// timeout after 10 minutes
+	cctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	for {
+		ev, ok := common.WaitFor(cctx, ctx.State, func(ev *gateway.InteractionCreateEvent) bool {
+			if ev.Message == nil || ev.Message.ID != msg.ID {
+				return false
+			}
+
+			data, ok := ev.Data.(discord.ComponentInteraction)
+			return ok && strings.HasPrefix(string(data.ID()), "ignores:")
+		})
+		if !ok {
+			// timed out, so remove the components
+			_, err = ctx.State.EditInteractionResponse(discord.AppID(bot.Me().ID), ctx.InteractionToken, api.EditInteractionResponseData{
+				Components: &discord.ContainerComponents{},
+			})
+			if err != nil {
+				log.Errorf("updating message for %v: %v", ctx.Event.ID, err)
+			}
+			return nil
+		}
+
+		data := ev.Data.(discord.ComponentInteraction)
+		if data.ID() == "ignores:close" {
+			err = ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+				Type: api.UpdateMessage,
+				Data: &api.InteractionResponseData{
+					Components: &discord.ContainerComponents{},
+				},
+			})
+			if err != nil {
+				log.Errorf("updating message for interaction %v: %v", ev.ID, err)
+			}
+			return nil
+		}
+
+		err = update(data)
+		if err != nil {
+			log.Errorf("updating ignores in guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "updating ignores"))
+		}
+
+		resp := response()
+		err = ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+			Type: api.UpdateMessage,
+			Data: &resp,
+		})
+		if err != nil {
+			log.Errorf("updating message for interaction %v: %v", ev.ID, err)
+			return nil
+		}
+	}
+}
+
+// userList returns a comma-separated list of the given users' mentions and tags.
+// Only cached users are used, as this is called before responding to interactions.
+func (bot *Bot) userList(guildID discord.GuildID, ids []discord.UserID) string {
+	if len(ids) == 0 {
+		return "None"
+	}
+
+	var names []string
+	for _, id := range ids {
+		u, ok := bot.CachedGuildUser(guildID, id)
+		if !ok {
+			names = append(names, id.Mention())
+			continue
+		}
+		names = append(names, fmt.Sprintf("%v (%v)", u.Mention(), u.Tag()))
+	}
+
+	return common.Truncate(strings.Join(names, ", "), 1000)
+}
+
+// roleList returns a comma-separated list of the given roles.
+func roleList(ids []discord.RoleID) string {
+	if len(ids) == 0 {
+		return "None"
+	}
+
+	var names []string
+	for _, id := range ids {
+		names = append(names, id.Mention())
+	}
+
+	return common.Truncate(strings.Join(names, ", "), 1000)
+}
+
+// channelList returns a comma-separated list of the given channels.
+func channelList(chs []discord.Channel, ids []discord.ChannelID) string {
+	if len(ids) == 0 {
+		return "None"
+	}
+
+	var names []string
+	for _, id := range ids {
+		names = append(names, channelString(chs, id))
+	}
+
+	return common.Truncate(strings.Join(names, ", "), 1000)
+}
+
+func (bot *Bot) ignoresChannels(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting guild channels"))
+	}
+
+	return bot.ignoreMenu(ctx, func() (discord.Embed, discord.ContainerComponents) {
+		return discord.Embed{
+			Title: "Ignored channels",
+			Description: `Messages in ignored channels are not logged. Ignoring a category also ignores all channels in it, and ignoring a channel also ignores its threads.
+Select a channel below to ignore it, or to stop ignoring it if it's already ignored.`,
+			Color: common.ColourPurple,
+			Fields: []discord.EmbedField{{
+				Name:  "Currently ignored",
+				Value: channelList(guildChannels, chs.Ignores.GlobalChannels),
+			}},
+		}, discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ChannelSelectComponent{
+					CustomID:     "ignores:channel",
+					Placeholder:  "Channel to ignore or unignore",
+					ChannelTypes: ignoreChannelTypes,
+				},
+			},
+		}
+	}, func(data discord.ComponentInteraction) error {
+		sel, ok := data.(*discord.ChannelSelectInteraction)
+		if !ok || len(sel.Values) == 0 {
+			return nil
+		}
+
+		chs.Ignores.GlobalChannels, _ = common.Toggle(chs.Ignores.GlobalChannels, sel.Values[0])
+		return bot.DB.SetChannels(ctx.Event.GuildID, chs)
+	})
+}
+
+func (bot *Bot) ignoresUsers(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	return bot.ignoreMenu(ctx, func() (discord.Embed, discord.ContainerComponents) {
+		return discord.Embed{
+			Title: "Ignored users and roles",
+			Description: `Messages by ignored users, and by members with an ignored role, are not logged in any channel.
+Select a user or role below to ignore it, or to stop ignoring it if it's already ignored.`,
+			Color: common.ColourPurple,
+			Fields: []discord.EmbedField{
+				{
+					Name:  "Ignored users",
+					Value: bot.userList(ctx.Event.GuildID, chs.Ignores.GlobalUsers),
+				},
+				{
+					Name:  "Ignored roles",
+					Value: roleList(chs.Ignores.GlobalRoles),
+				},
+			},
+		}, discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.UserSelectComponent{
+					CustomID:    "ignores:user",
+					Placeholder: "User to ignore or unignore",
+				},
+			},
+			&discord.ActionRowComponent{
+				&discord.RoleSelectComponent{
+					CustomID:    "ignores:role",
+					Placeholder: "Role to ignore or unignore",
+				},
+			},
+		}
+	}, func(data discord.ComponentInteraction) error {
+		switch sel := data.(type) {
+		case *discord.UserSelectInteraction:
+			if len(sel.Values) == 0 {
+				return nil
+			}
+			chs.Ignores.GlobalUsers, _ = common.Toggle(chs.Ignores.GlobalUsers, sel.Values[0])
+		case *discord.RoleSelectInteraction:
+			if len(sel.Values) == 0 {
+				return nil
+			}
+			chs.Ignores.GlobalRoles, _ = common.Toggle(chs.Ignores.GlobalRoles, sel.Values[0])
+		default:
+			return nil
+		}
+
+		return bot.DB.SetChannels(ctx.Event.GuildID, chs)
+	})
+}
+
+func (bot *Bot) ignoresChannelUsers(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting guild channels"))
+	}
+
+	var channelID discord.ChannelID
+
+	return bot.ignoreMenu(ctx, func() (discord.Embed, discord.ContainerComponents) {
+		e := discord.Embed{
+			Title: "Ignored users and roles per channel",
+			Description: `Messages by these users, and by members with these roles, are not logged in the selected channel, its threads, or (for categories) the channels in it.
+First select a channel, then select a user or role to ignore it in that channel, or to stop ignoring it if it's already ignored.`,
+			Color: common.ColourPurple,
+		}
+		if channelID.IsValid() {
+			e.Fields = append(e.Fields, discord.EmbedField{
+				Name:  "Users ignored in " + channelName(guildChannels, channelID),
+				Value: bot.userList(ctx.Event.GuildID, chs.Ignores.PerChannel[channelID.String()]),
+			}, discord.EmbedField{
+				Name:  "Roles ignored in " + channelName(guildChannels, channelID),
+				Value: roleList(chs.Ignores.PerChannelRoles[channelID.String()]),
+			})
+		}
+
+		return e, discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ChannelSelectComponent{
+					CustomID:     "ignores:channel",
+					Placeholder:  "Channel",
+					ChannelTypes: ignoreChannelTypes,
+				},
+			},
+			&discord.ActionRowComponent{
+				&discord.UserSelectComponent{
+					CustomID:    "ignores:user",
+					Placeholder: "User to ignore or unignore in this channel",
+					Disabled:    !channelID.IsValid(),
+				},
+			},
+			&discord.ActionRowComponent{
+				&discord.RoleSelectComponent{
+					CustomID:    "ignores:role",
+					Placeholder: "Role to ignore or unignore in this channel",
+					Disabled:    !channelID.IsValid(),
+				},
+			},
+		}
+	}, func(data discord.ComponentInteraction) error {
+		switch sel := data.(type) {
+		case *discord.ChannelSelectInteraction:
+			if len(sel.Values) > 0 {
+				channelID = sel.Values[0]
+			}
+			return nil
+		case *discord.UserSelectInteraction:
+			if len(sel.Values) == 0 || !channelID.IsValid() {
+				return nil
+			}
+
+			if chs.Ignores.PerChannel == nil {
+				chs.Ignores.PerChannel = make(map[string][]discord.UserID)
+			}
+
+			users, _ := common.Toggle(chs.Ignores.PerChannel[channelID.String()], sel.Values[0])
+			if len(users) == 0 {
+				delete(chs.Ignores.PerChannel, channelID.String())
+			} else {
+				chs.Ignores.PerChannel[channelID.String()] = users
+			}
+			return bot.DB.SetChannels(ctx.Event.GuildID, chs)
+		case *discord.RoleSelectInteraction:
+			if len(sel.Values) == 0 || !channelID.IsValid() {
+				return nil
+			}
+
+			if chs.Ignores.PerChannelRoles == nil {
+				chs.Ignores.PerChannelRoles = make(map[string][]discord.RoleID)
+			}
+
+			roles, _ := common.Toggle(chs.Ignores.PerChannelRoles[channelID.String()], sel.Values[0])
+			if len(roles) == 0 {
+				delete(chs.Ignores.PerChannelRoles, channelID.String())
+			} else {
+				chs.Ignores.PerChannelRoles[channelID.String()] = roles
+			}
+			return bot.DB.SetChannels(ctx.Event.GuildID, chs)
+		}
+		return nil
+	})
+}
+
+func (bot *Bot) ignoresList(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting guild channels"))
+	}
+
+	e := discord.Embed{
+		Title: "Ignored channels, users, and roles",
+		Color: common.ColourPurple,
+		Fields: []discord.EmbedField{
+			{Name: "Channels", Value: channelList(guildChannels, chs.Ignores.GlobalChannels)},
+			{Name: "Users", Value: bot.userList(ctx.Event.GuildID, chs.Ignores.GlobalUsers)},
+			{Name: "Roles", Value: roleList(chs.Ignores.GlobalRoles)},
+		},
+	}
+
+	channelSet := common.NewSet[string]()
+	for id, users := range chs.Ignores.PerChannel {
+		if len(users) > 0 {
+			channelSet.Add(id)
+		}
+	}
+	for id, roles := range chs.Ignores.PerChannelRoles {
+		if len(roles) > 0 {
+			channelSet.Add(id)
+		}
+	}
+	channels := channelSet.Values()
+	sort.Strings(channels)
+
+	for i, id := range channels {
+		// embeds can have at most 25 fields
+		if len(e.Fields) >= 24 {
+			e.Fields = append(e.Fields, discord.EmbedField{
+				Name:  "\u200b",
+				Value: fmt.Sprintf("...and %v more channels with ignored users or roles", len(channels)-i),
+			})
+			break
+		}
+
+		var value string
+		if users := chs.Ignores.PerChannel[id]; len(users) > 0 {
+			value += "**Users:** " + bot.userList(ctx.Event.GuildID, users) + "\n"
+		}
+		if roles := chs.Ignores.PerChannelRoles[id]; len(roles) > 0 {
+			value += "**Roles:** " + roleList(roles)
+		}
+
+		sf, _ := discord.ParseSnowflake(id)
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Ignored in " + channelName(guildChannels, discord.ChannelID(sf)),
+			Value: value,
+		})
+	}
+
+	return ctx.ReplyEphemeral("", e)
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v5"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// linksAdd links another server's logs to a channel in this server.
+// The user must have Manage Server in both the destination server (where the command is run) and the source server.
+// Server admins can change who can use the command, so this is checked explicitly rather than left to Discord,
+// and the link is removed if the user loses it in either server (see bot.CheckLogLinks).
+func (bot *Bot) linksAdd(ctx *bcr.CommandContext) (err error) {
+	opts := bot.Options(ctx)
+
+	sf, err := discord.ParseSnowflake(strings.TrimSpace(opts.Find("server").String()))
+	if err != nil || !sf.IsValid() {
+		return ctx.ReplyEphemeral("That isn't a valid server ID.")
+	}
+	sourceID := discord.GuildID(sf)
+
+	if sourceID == ctx.Event.GuildID {
+		return ctx.ReplyEphemeral("A server's logs can't be linked to itself. Use `/config channels` instead.")
+	}
+
+	chID, err := opts.Find("channel").SnowflakeValue()
+	if err != nil || !chID.IsValid() {
+		return ctx.ReplyEphemeral("You must give a channel.")
+	}
+	channelID := discord.ChannelID(chID)
+
+	ch, err := bot.Cabinet.Channel(context.Background(), channelID)
+	if err != nil {
+		log.Errorf("getting channel %v: %v", channelID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting channel"))
+	}
+	if ch.GuildID != ctx.Event.GuildID || !common.Contains(common.LogChannelTypes, ch.Type) {
+		return ctx.ReplyEphemeral("The log channel must be a text channel, announcement channel, voice channel, or thread in this server.")
+	}
+
+	ok, err := bot.HasGuildPermission(ctx.Event.GuildID, ctx.User.ID, discord.PermissionManageGuild)
+	if err != nil {
+		log.Errorf("checking permissions for %v in guild %v: %v", ctx.User.ID, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "checking permissions"))
+	}
+	if !ok {
+		return ctx.ReplyEphemeral("You need the Manage Server permission in this server to link another server's logs to it.")
+	}
+
+	source, err := bot.Cabinet.Guild(context.Background(), sourceID)
+	if err != nil {
+		return ctx.ReplyEphemeral("I'm not in that server, so I can't log it.")
+	}
+
+	ok, err = bot.HasGuildPermission(sourceID, ctx.User.ID, discord.PermissionManageGuild)
+	if err != nil {
+		log.Errorf("checking permissions for %v in guild %v: %v", ctx.User.ID, sourceID, err)
+		return ctx.ReplyEphemeral(fmt.Sprintf("I couldn't check your permissions in **%v**. Are you a member of it?", source.Name))
+	}
+	if !ok {
+		return ctx.ReplyEphemeral(fmt.Sprintf("You need the Manage Server permission in **%v** to link its logs.", source.Name))
+	}
+
+	err = bot.DB.SetLogLink(db.LogLink{
+		SourceID:  sourceID,
+		GuildID:   ctx.Event.GuildID,
+		ChannelID: channelID,
+		CreatedBy: ctx.User.ID,
+	})
+	if err != nil {
+		log.Errorf("linking guild %v to channel %v: %v", sourceID, channelID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "setting log link"))
+	}
+
+	bot.InvalidateGuildSettings(sourceID)
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("All logs from **%v** will now also be sent to %v.", source.Name, channelID.Mention()))
+}
+
+// linksRemove removes a link to or from this server.
+// With a server ID, it stops that server's logs from being sent here;
+// without one, it stops this server's logs from being sent to another server.
+func (bot *Bot) linksRemove(ctx *bcr.CommandContext) (err error) {
+	opt := bot.Options(ctx).Find("server")
+	if opt.Name == "" {
+		removed, err := bot.DB.DeleteOutgoingLogLink(ctx.Event.GuildID)
+		if err != nil {
+			log.Errorf("removing log link for guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "removing log link"))
+		}
+
+		bot.InvalidateGuildSettings(ctx.Event.GuildID)
+
+		if !removed {
+			return ctx.ReplyEphemeral("This server's logs aren't sent to another server.")
+		}
+		return ctx.ReplyEphemeral("This server's logs will no longer be sent to another server.")
+	}
+
+	sf, err := discord.ParseSnowflake(strings.TrimSpace(opt.String()))
+	if err != nil || !sf.IsValid() {
+		return ctx.ReplyEphemeral("That isn't a valid server ID.")
+	}
+
+	removed, err := bot.DB.DeleteLogLink(ctx.Event.GuildID, discord.GuildID(sf))
+	if err != nil {
+		log.Errorf("removing log link for guild %v in %v: %v", sf, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "removing log link"))
+	}
+
+	bot.InvalidateGuildSettings(discord.GuildID(sf))
+
+	if !removed {
+		return ctx.ReplyEphemeral("That server's logs aren't linked to this server.")
+	}
+	return ctx.ReplyEphemeral("That server's logs will no longer be sent to this server.")
+}
+
+// linksList lists the servers whose logs are sent to this server, and the server this server's logs are sent to.
+func (bot *Bot) linksList(ctx *bcr.CommandContext) (err error) {
+	links, err := bot.DB.LogLinksTo(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log links for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log links"))
+	}
+
+	outgoing, err := bot.DB.LogLink(ctx.Event.GuildID)
+	hasOutgoing := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Errorf("getting log link for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log links"))
+	}
+
+	if len(links) == 0 && !hasOutgoing {
+		return ctx.ReplyEphemeral("This server isn't linked to any other servers.")
+	}
+
+	e := discord.Embed{
+		Title: "Linked servers",
+		Color: common.ColourPurple,
+	}
+
+	if hasOutgoing {
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name: "This server's logs are sent to",
+			Value: fmt.Sprintf("%v (`%v`) in %v (`%v`), linked by %v\nUse `/config links remove` without a server to unlink it.",
+				outgoing.ChannelID.Mention(), outgoing.ChannelID, bot.guildName(outgoing.GuildID), outgoing.GuildID, outgoing.CreatedBy.Mention()),
+		})
+	}
+
+	if len(links) > 0 {
+		var lines []string
+		for _, l := range links {
+			lines = append(lines, fmt.Sprintf("%v (`%v`) → %v, linked by %v", bot.guildName(l.SourceID), l.SourceID, l.ChannelID.Mention(), l.CreatedBy.Mention()))
+		}
+		e.Description = strings.Join(lines, "\n")
+	}
+
+	return ctx.ReplyEphemeral("", e)
+}
+
+// guildName returns the guild's name in bold, or a placeholder if the bot isn't in it.
+func (bot *Bot) guildName(id discord.GuildID) string {
+	if g, err := bot.Cabinet.Guild(context.Background(), id); err == nil {
+		return "**" + g.Name + "**"
+	}
+	return "*unknown server*"
+}
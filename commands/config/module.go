@@ -5,6 +5,15 @@ import (
 	"github.com/starshine-sys/catalogger/v2/common/log"
 )
 
+type EventSubject = bot.EventSubject
+
+// Batching limits are aliased here, as the bot package is shadowed by the command receivers.
+const (
+	maxBatchEmbeds = bot.MaxBatchEmbeds
+	maxBatchLength = bot.MaxBatchLength
+	maxBatchDelay  = bot.MaxBatchDelay
+)
+
 type Bot struct {
 	*bot.Bot
 }
@@ -15,4 +24,37 @@ func Setup(root *bot.Bot) {
 	bot := &Bot{Bot: root}
 
 	bot.Router.Command("config/channels").Exec(bot.channelsEntry)
+	bot.Router.Command("config/unknown-messages").Exec(bot.unknownMessages)
+	bot.Router.Command("config/explain").Exec(bot.explain)
+	bot.Router.Command("config/dossiers").Exec(bot.dossiers)
+	bot.Router.Command("config/ghost-pings").Exec(bot.ghostPings)
+	bot.Router.Command("config/status").Exec(bot.status)
+
+	bot.Router.Command("config/banned-systems/add").Exec(bot.bannedSystemsAdd)
+	bot.Router.Command("config/banned-systems/remove").Exec(bot.bannedSystemsRemove)
+	bot.Router.Command("config/banned-systems/list").Exec(bot.bannedSystemsList)
+
+	bot.Router.Command("config/redirects/add").Exec(bot.redirectsAdd)
+	bot.Router.Command("config/redirects/remove").Exec(bot.redirectsRemove)
+	bot.Router.Command("config/redirects/list").Exec(bot.redirectsList)
+
+	bot.Router.Command("config/ignores/channels").Exec(bot.ignoresChannels)
+	bot.Router.Command("config/ignores/users").Exec(bot.ignoresUsers)
+	bot.Router.Command("config/ignores/channel-users").Exec(bot.ignoresChannelUsers)
+	bot.Router.Command("config/ignores/list").Exec(bot.ignoresList)
+
+	bot.Router.Command("config/ignore-rules/add").Exec(bot.ignoreRulesAdd)
+	bot.Router.Command("config/ignore-rules/remove").Exec(bot.ignoreRulesRemove)
+	bot.Router.Command("config/ignore-rules/list").Exec(bot.ignoreRulesList)
+
+	bot.Router.Command("config/alerts/set").Exec(bot.alertsSet)
+	bot.Router.Command("config/alerts/list").Exec(bot.alertsList)
+
+	bot.Router.Command("config/batching/set").Exec(bot.batchingSet)
+	bot.Router.Command("config/batching/reset").Exec(bot.batchingReset)
+	bot.Router.Command("config/batching/list").Exec(bot.batchingList)
+
+	bot.Router.Command("config/links/add").Exec(bot.linksAdd)
+	bot.Router.Command("config/links/remove").Exec(bot.linksRemove)
+	bot.Router.Command("config/links/list").Exec(bot.linksList)
 }
@@ -0,0 +1,516 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// redirectLinesPerPage is the number of lines shown per page in the redirect list.
+const redirectLinesPerPage = 20
+
+// channelString returns a readable name for the given channel.
+// Categories can't be mentioned, so their name is returned instead.
+func channelString(chs []discord.Channel, id discord.ChannelID) string {
+	for _, ch := range chs {
+		if ch.ID != id {
+			continue
+		}
+
+		if ch.Type == discord.GuildCategory {
+			return fmt.Sprintf("📁 **%v**", ch.Name)
+		}
+		return id.Mention()
+	}
+	return "*unknown channel " + id.String() + "*"
+}
+
+// channelName returns the plain name of the given channel, for use in select menus.
+func channelName(chs []discord.Channel, id discord.ChannelID) string {
+	for _, ch := range chs {
+		if ch.ID == id {
+			if ch.Type == discord.GuildCategory {
+				return ch.Name
+			}
+			return "#" + ch.Name
+		}
+	}
+	return "unknown channel " + id.String()
+}
+
+func (bot *Bot) redirectsAdd(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	var (
+		source, target discord.ChannelID
+		problem        string
+	)
+
+	embed := func() []discord.Embed {
+		sourceString, targetString := "Not selected", "Not selected"
+		if source.IsValid() {
+			sourceString = source.Mention()
+		}
+		if target.IsValid() {
+			targetString = target.Mention()
+		}
+
+		e := discord.Embed{
+			Title: "Add a redirect",
+			Description: `Message logs for the source channel will be sent to the target channel instead of the normal log channel.
+If the source is a category, this applies to all channels in it, unless they have their own redirect.
+Threads use their parent channel's redirect.`,
+			Color: common.ColourPurple,
+			Fields: []discord.EmbedField{
+				{Name: "Source", Value: sourceString, Inline: true},
+				{Name: "Target", Value: targetString, Inline: true},
+			},
+		}
+		if problem != "" {
+			e.Fields = append(e.Fields, discord.EmbedField{Name: "Problem", Value: problem})
+		}
+		return []discord.Embed{e}
+	}
+
+	components := func() *discord.ContainerComponents {
+		return &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ChannelSelectComponent{
+					CustomID:    "redirect:source",
+					Placeholder: "Redirect logs from...",
+					ChannelTypes: []discord.ChannelType{
+						discord.GuildText, discord.GuildAnnouncement, discord.GuildCategory, discord.GuildForum,
+						discord.GuildPublicThread, discord.GuildPrivateThread, discord.GuildAnnouncementThread,
+					},
+				},
+			},
+			&discord.ActionRowComponent{
+				&discord.ChannelSelectComponent{
+					CustomID:     "redirect:target",
+					Placeholder:  "...to this channel",
+					ChannelTypes: common.LogChannelTypes,
+				},
+			},
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					CustomID: "redirect:save",
+					Label:    "Save",
+					Style:    discord.SuccessButtonStyle(),
+					Disabled: !source.IsValid() || !target.IsValid(),
+				},
+				&discord.ButtonComponent{
+					CustomID: "redirect:cancel",
+					Label:    "Cancel",
+					Style:    discord.SecondaryButtonStyle(),
+				},
+			},
+		}
+	}
+
+	initial := embed()
+	err = ctx.ReplyComplex(api.InteractionResponseData{
+		Embeds:     &initial,
+		Components: components(),
+	})
+	if err != nil {
+		log.Errorf("sending interaction response for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "sending initial embed"))
+	}
+
+	msg, err := ctx.Original()
+	if err != nil {
+		log.Errorf("getting original message for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting original message"))
+	}
+
+	// timeout after 10 minutes
+	cctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	for {
+		ev, ok := common.WaitFor(cctx, ctx.State, func(ev *gateway.InteractionCreateEvent) bool {
+			if ev.Message == nil || ev.Message.ID != msg.ID {
+				return false
+			}
+
+			switch data := ev.Data.(type) {
+			case *discord.ChannelSelectInteraction:
+				return strings.HasPrefix(string(data.CustomID), "redirect:")
+			case *discord.ButtonInteraction:
+				return strings.HasPrefix(string(data.CustomID), "redirect:")
+			}
+			return false
+		})
+		if !ok {
+			// timed out, so remove the components
+			_, err = ctx.State.EditInteractionResponse(discord.AppID(bot.Me().ID), ctx.InteractionToken, api.EditInteractionResponseData{
+				Components: &discord.ContainerComponents{},
+			})
+			if err != nil {
+				log.Errorf("updating message for %v: %v", ctx.Event.ID, err)
+			}
+			return nil
+		}
+
+		problem = ""
+		done := false
+		var result string
+
+		switch data := ev.Data.(type) {
+		case *discord.ChannelSelectInteraction:
+			if len(data.Values) == 0 {
+				break
+			}
+
+			if data.CustomID == "redirect:source" {
+				source = data.Values[0]
+			} else {
+				target = data.Values[0]
+			}
+		case *discord.ButtonInteraction:
+			if data.CustomID == "redirect:cancel" {
+				done = true
+				result = "Cancelled, no redirect was added."
+				break
+			}
+
+			problem, err = bot.validateRedirect(ctx.Event.GuildID, source, target)
+			if err != nil {
+				log.Errorf("validating redirect from %v to %v: %v", source, target, err)
+				return bot.ReportError(ctx, errors.Wrap(err, "validating redirect"))
+			}
+			if problem != "" {
+				break
+			}
+
+			if chs.Redirects == nil {
+				chs.Redirects = make(db.Redirects)
+			}
+			chs.Redirects[source.String()] = target
+
+			err = bot.DB.SetChannels(ctx.Event.GuildID, chs)
+			if err != nil {
+				log.Errorf("setting channels in guild %v: %v", ctx.Event.GuildID, err)
+				return bot.ReportError(ctx, errors.Wrap(err, "saving redirect"))
+			}
+
+			done = true
+			result = fmt.Sprintf("Message logs for %v will now be sent to %v.", source.Mention(), target.Mention())
+		}
+
+		resp := api.InteractionResponseData{}
+		if done {
+			resp.Embeds = &[]discord.Embed{{
+				Title:       "Add a redirect",
+				Description: result,
+				Color:       common.ColourPurple,
+			}}
+			resp.Components = &discord.ContainerComponents{}
+		} else {
+			updated := embed()
+			resp.Embeds = &updated
+			resp.Components = components()
+		}
+
+		err = ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+			Type: api.UpdateMessage,
+			Data: &resp,
+		})
+		if err != nil {
+			log.Errorf("updating message for interaction %v: %v", ev.ID, err)
+			return nil
+		}
+
+		if done {
+			return nil
+		}
+	}
+}
+
+// validateRedirect checks whether a redirect from source to target is valid.
+// If it isn't, problem is a user-facing description of why.
+func (bot *Bot) validateRedirect(guildID discord.GuildID, source, target discord.ChannelID) (problem string, err error) {
+	if !source.IsValid() || !target.IsValid() {
+		return "You must select both a source and a target channel.", nil
+	}
+	if source == target {
+		return "A channel can't be redirected to itself.", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch, err := bot.Cabinet.Channel(ctx, target)
+	if err != nil {
+		return "", errors.Wrap(err, "getting target channel")
+	}
+	if ch.GuildID != guildID {
+		return "The target channel must be in this server.", nil
+	}
+	if !common.Contains(common.LogChannelTypes, ch.Type) {
+		return "The target channel must be a text channel, announcement channel, voice channel, or thread.", nil
+	}
+
+	ch, err = bot.Cabinet.Channel(ctx, source)
+	if err != nil {
+		return "", errors.Wrap(err, "getting source channel")
+	}
+	if ch.GuildID != guildID {
+		return "The source channel must be in this server.", nil
+	}
+
+	return "", nil
+}
+
+func (bot *Bot) redirectsRemove(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	if len(chs.Redirects) == 0 {
+		return ctx.ReplyEphemeral("There are no redirects in this server.")
+	}
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting guild channels"))
+	}
+
+	// if a source is given, remove its redirect directly
+	if sf, err := bot.Options(ctx).Find("source").SnowflakeValue(); err == nil && sf.IsValid() {
+		source := discord.ChannelID(sf)
+		if _, ok := chs.Redirects[source.String()]; !ok {
+			return ctx.ReplyEphemeral(fmt.Sprintf("%v isn't redirected.", channelString(guildChannels, source)))
+		}
+
+		delete(chs.Redirects, source.String())
+		err = bot.DB.SetChannels(ctx.Event.GuildID, chs)
+		if err != nil {
+			log.Errorf("setting channels in guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "removing redirect"))
+		}
+
+		return ctx.ReplyEphemeral(fmt.Sprintf("Removed the redirect for %v. Its message logs will now be sent to the normal log channel.",
+			channelString(guildChannels, source)))
+	}
+
+	sources := make([]string, 0, len(chs.Redirects))
+	for source := range chs.Redirects {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	description := "Select the redirect to remove below."
+
+	// select menus can only have 25 options, the rest can only be removed with the source option
+	if len(sources) > 25 {
+		description += fmt.Sprintf("\nOnly 25 of %v redirects are shown, use the `source` option to remove any others.", len(sources))
+		sources = sources[:25]
+	}
+
+	var options []discord.SelectOption
+	for _, source := range sources {
+		sourceID, _ := discord.ParseSnowflake(source)
+		options = append(options, discord.SelectOption{
+			Label: channelName(guildChannels, discord.ChannelID(sourceID)),
+			Value: source,
+			Description: "Redirected to " +
+				channelName(guildChannels, chs.Redirects[source]),
+		})
+	}
+
+	err = ctx.ReplyComplex(api.InteractionResponseData{
+		Embeds: &[]discord.Embed{{
+			Title:       "Remove a redirect",
+			Description: description,
+			Color:       common.ColourPurple,
+		}},
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.StringSelectComponent{
+					CustomID:    "redirect:remove",
+					Placeholder: "Redirect to remove",
+					Options:     options,
+				},
+			},
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					CustomID: "redirect:cancel",
+					Label:    "Cancel",
+					Style:    discord.SecondaryButtonStyle(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Errorf("sending interaction response for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "sending initial embed"))
+	}
+
+	msg, err := ctx.Original()
+	if err != nil {
+		log.Errorf("getting original message for %v: %v", ctx.Event.ID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting original message"))
+	}
+
+	cctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ev, ok := common.WaitFor(cctx, ctx.State, func(ev *gateway.InteractionCreateEvent) bool {
+		if ev.Message == nil || ev.Message.ID != msg.ID {
+			return false
+		}
+
+		switch ev.Data.(type) {
+		case *discord.StringSelectInteraction, *discord.ButtonInteraction:
+			return true
+		}
+		return false
+	})
+	if !ok {
+		_, err = ctx.State.EditInteractionResponse(discord.AppID(bot.Me().ID), ctx.InteractionToken, api.EditInteractionResponseData{
+			Components: &discord.ContainerComponents{},
+		})
+		if err != nil {
+			log.Errorf("updating message for %v: %v", ctx.Event.ID, err)
+		}
+		return nil
+	}
+
+	result := "Cancelled, no redirect was removed."
+	if data, ok := ev.Data.(*discord.StringSelectInteraction); ok && len(data.Values) > 0 {
+		source := data.Values[0]
+		sourceID, _ := discord.ParseSnowflake(source)
+
+		delete(chs.Redirects, source)
+		err = bot.DB.SetChannels(ctx.Event.GuildID, chs)
+		if err != nil {
+			log.Errorf("setting channels in guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "removing redirect"))
+		}
+
+		result = fmt.Sprintf("Removed the redirect for %v. Its message logs will now be sent to the normal log channel.",
+			channelString(guildChannels, discord.ChannelID(sourceID)))
+	}
+
+	err = ctx.State.RespondInteraction(ev.ID, ev.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds: &[]discord.Embed{{
+				Title:       "Remove a redirect",
+				Description: result,
+				Color:       common.ColourPurple,
+			}},
+			Components: &discord.ContainerComponents{},
+		},
+	})
+	if err != nil {
+		log.Errorf("updating message for interaction %v: %v", ev.ID, err)
+	}
+	return nil
+}
+
+func (bot *Bot) redirectsList(ctx *bcr.CommandContext) (err error) {
+	chs, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting log channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting log channels"))
+	}
+
+	if len(chs.Redirects) == 0 {
+		return ctx.ReplyEphemeral("There are no redirects in this server.")
+	}
+
+	guildChannels, err := bot.Cabinet.Channels(context.Background(), ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting guild channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting guild channels"))
+	}
+
+	sort.Slice(guildChannels, func(i, j int) bool {
+		if guildChannels[i].Position == guildChannels[j].Position {
+			return guildChannels[i].ID < guildChannels[j].ID
+		}
+		return guildChannels[i].Position < guildChannels[j].Position
+	})
+
+	var lines []string
+
+	// configured redirects
+	lines = append(lines, "**Redirects**")
+	sources := make([]string, 0, len(chs.Redirects))
+	for source := range chs.Redirects {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		sourceID, _ := discord.ParseSnowflake(source)
+		lines = append(lines, fmt.Sprintf("%v → %v",
+			channelString(guildChannels, discord.ChannelID(sourceID)), channelString(guildChannels, chs.Redirects[source])))
+	}
+
+	// channels that are redirected through their category
+	var inherited []string
+	for _, ch := range guildChannels {
+		if ch.Type == discord.GuildCategory || common.IsThread(ch) || !ch.ParentID.IsValid() {
+			continue
+		}
+		if _, ok := chs.Redirects[ch.ID.String()]; ok {
+			continue
+		}
+
+		if target, ok := chs.Redirects[ch.ParentID.String()]; ok {
+			inherited = append(inherited, fmt.Sprintf("%v → %v (from %v)",
+				ch.ID.Mention(), channelString(guildChannels, target), channelString(guildChannels, ch.ParentID)))
+		}
+	}
+	if len(inherited) > 0 {
+		lines = append(lines, "", "**Redirected through their category**")
+		lines = append(lines, inherited...)
+	}
+
+	lines = append(lines, "",
+		fmt.Sprintf("Other channels use the normal log channels (edits: %v, deletes: %v).",
+			logChannelString(guildChannels, chs.Channels.MessageUpdate), logChannelString(guildChannels, chs.Channels.MessageDelete)))
+
+	var embeds []discord.Embed
+	for i := 0; i < len(lines); i += redirectLinesPerPage {
+		end := i + redirectLinesPerPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		embeds = append(embeds, discord.Embed{
+			Title:       fmt.Sprintf("Redirects (%v)", len(chs.Redirects)),
+			Description: strings.Join(lines[i:end], "\n"),
+			Color:       common.ColourPurple,
+		})
+	}
+
+	return bot.PaginateEmbeds(ctx, embeds)
+}
+
+func logChannelString(chs []discord.Channel, id discord.ChannelID) string {
+	if !id.IsValid() {
+		return "not set"
+	}
+	return channelString(chs, id)
+}
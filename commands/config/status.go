@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// statusLinesPerPage is the number of destinations shown per page in /config status.
+const statusLinesPerPage = 15
+
+func (bot *Bot) status(ctx *bcr.CommandContext) (err error) {
+	lc, err := bot.DB.Channels(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting channels for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting channels"))
+	}
+
+	stats, err := bot.DB.DeliveryStats(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting delivery stats for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting delivery stats"))
+	}
+
+	byEvent := map[string][]db.DeliveryStat{}
+	for _, s := range stats {
+		byEvent[s.Event] = append(byEvent[s.Event], s)
+	}
+
+	logChannels := map[string]discord.ChannelID{}
+	for _, ev := range common.LogEvents {
+		if id := lc.Channels.ForKey(ev.Key); id.IsValid() {
+			logChannels[ev.Key] = id
+		}
+	}
+
+	var (
+		lines   []string
+		failing int
+	)
+	addLines := func(event string) {
+		for _, s := range byEvent[event] {
+			if s.Failing() {
+				failing++
+			}
+			lines = append(lines, statusLine(s))
+		}
+		delete(byEvent, event)
+	}
+
+	for _, ev := range common.Events {
+		if len(byEvent[ev.Value]) == 0 {
+			if id, ok := logChannels[ev.Value]; ok {
+				lines = append(lines, fmt.Sprintf("➖ **%v** → %v: nothing logged yet", ev.Name, id.Mention()))
+			}
+			continue
+		}
+		addLines(ev.Value)
+	}
+
+	// events that can't be configured in /config channels, such as member dossiers
+	var rest []string
+	for event := range byEvent {
+		rest = append(rest, event)
+	}
+	sort.Strings(rest)
+	for _, event := range rest {
+		addLines(event)
+	}
+
+	if len(lines) == 0 {
+		return ctx.ReplyEphemeral("This server doesn't have any log channels yet.")
+	}
+
+	title := "Delivery status"
+	colour := common.ColourGreen
+	if failing > 0 {
+		title = fmt.Sprintf("Delivery status (%v failing)", failing)
+		colour = common.ColourRed
+	}
+
+	var embeds []discord.Embed
+	for i := 0; i < len(lines); i += statusLinesPerPage {
+		end := i + statusLinesPerPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		embeds = append(embeds, discord.Embed{
+			Title:       title,
+			Description: strings.Join(lines[i:end], "\n"),
+			Color:       colour,
+		})
+	}
+
+	return bot.PaginateEmbeds(ctx, embeds)
+}
+
+// statusLine returns a readable summary of an event's deliveries to a log channel.
+func statusLine(s db.DeliveryStat) string {
+	name := common.EventName(s.Event)
+
+	if s.Failing() {
+		reason := "unknown error"
+		if s.LastError != nil {
+			reason = *s.LastError
+		}
+
+		line := fmt.Sprintf("❌ **%v** → %v: %v failed in a row", name, s.ChannelID.Mention(), s.ConsecutiveFailures)
+		if s.LastSuccess != nil {
+			line += fmt.Sprintf(", last delivered <t:%v:R>", s.LastSuccess.Unix())
+		}
+		return line + fmt.Sprintf("\n> `%v`", reason)
+	}
+
+	line := fmt.Sprintf("✅ **%v** → %v: %v delivered", name, s.ChannelID.Mention(), s.Successes)
+	if s.Failures > 0 {
+		line += fmt.Sprintf(", %v failed", s.Failures)
+	}
+	if s.LastSuccess != nil {
+		line += fmt.Sprintf(", last <t:%v:R>", s.LastSuccess.Unix())
+	}
+	return line
+}
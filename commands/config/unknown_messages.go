@@ -0,0 +1,41 @@
+package config
+
+import (
+	"emperror.dev/errors"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+func (bot *Bot) unknownMessages(ctx *bcr.CommandContext) (err error) {
+	opt := bot.Options(ctx).Find("enabled")
+
+	// if no value is given, show the current setting
+	if opt.Name == "" {
+		enabled, err := bot.DB.LogUnknownMessages(ctx.Event.GuildID)
+		if err != nil {
+			log.Errorf("getting unknown message setting for guild %v: %v", ctx.Event.GuildID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "getting setting"))
+		}
+
+		if enabled {
+			return ctx.ReplyEphemeral("Deletions of messages that Catalogger did not store are currently **logged**.")
+		}
+		return ctx.ReplyEphemeral("Deletions of messages that Catalogger did not store are currently **not logged**.")
+	}
+
+	enabled, err := opt.BoolValue()
+	if err != nil {
+		return ctx.ReplyEphemeral("That isn't a valid value.")
+	}
+
+	err = bot.DB.SetLogUnknownMessages(ctx.Event.GuildID, enabled)
+	if err != nil {
+		log.Errorf("setting unknown message setting for guild %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "updating setting"))
+	}
+
+	if enabled {
+		return ctx.ReplyEphemeral("Deletions of messages that Catalogger did not store will now be logged to the deleted messages log channel.")
+	}
+	return ctx.ReplyEphemeral("Deletions of messages that Catalogger did not store will no longer be logged.")
+}
@@ -0,0 +1,176 @@
+package invites
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// invitesPerPage is the number of invites shown per page in the list command.
+const invitesPerPage = 8
+
+// inviteCode returns the invite code given in the "invite" option.
+// Full invite links are also accepted.
+func (bot *Bot) inviteCode(ctx *bcr.CommandContext) string {
+	code := strings.TrimSpace(bot.Options(ctx).Find("invite").String())
+	code = strings.TrimPrefix(code, "https://")
+	code = strings.TrimPrefix(code, "http://")
+	for _, prefix := range []string{"discord.gg/", "discord.com/invite/", "discordapp.com/invite/"} {
+		code = strings.TrimPrefix(code, prefix)
+	}
+	return code
+}
+
+// guildInvite returns the guild's invite with the given code.
+// The cached invites are checked first, then the invites are fetched from Discord.
+func (bot *Bot) guildInvite(guildID discord.GuildID, code string) (inv discord.Invite, ok bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	invs, err := bot.Cabinet.Invites(ctx, guildID)
+	if err == nil {
+		for _, i := range invs {
+			if i.Code == code {
+				return i, true, nil
+			}
+		}
+	}
+
+	invs, err = bot.Router.Rest.GuildInvites(guildID)
+	if err != nil {
+		return inv, false, errors.Wrap(err, "getting guild invites")
+	}
+
+	for _, i := range invs {
+		if i.Code == code {
+			return i, true, nil
+		}
+	}
+	return inv, false, nil
+}
+
+func (bot *Bot) list(ctx *bcr.CommandContext) (err error) {
+	cctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	invs, err := bot.Cabinet.Invites(cctx, ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting cached invites for %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting cached invites"))
+	}
+
+	if len(invs) == 0 {
+		return ctx.ReplyEphemeral("This server has no invites.")
+	}
+
+	names, err := bot.DB.InviteNames(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting invite names for %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting invite names"))
+	}
+
+	// most used invites first
+	sort.Slice(invs, func(i, j int) bool {
+		if invs[i].Uses == invs[j].Uses {
+			return invs[i].Code < invs[j].Code
+		}
+		return invs[i].Uses > invs[j].Uses
+	})
+
+	var embeds []discord.Embed
+	for i := 0; i < len(invs); i += invitesPerPage {
+		end := i + invitesPerPage
+		if end > len(invs) {
+			end = len(invs)
+		}
+
+		e := discord.Embed{
+			Title: fmt.Sprintf("Invites (%v)", len(invs)),
+			Color: common.ColourPurple,
+		}
+
+		for _, inv := range invs[i:end] {
+			name := "Unnamed"
+			if n, ok := names[inv.Code]; ok {
+				name = n
+			}
+
+			uses := fmt.Sprint(inv.Uses)
+			if inv.MaxUses != 0 {
+				uses += fmt.Sprintf("/%v", inv.MaxUses)
+			}
+
+			inviter := "Unknown"
+			if inv.Inviter != nil {
+				inviter = inv.Inviter.Mention()
+			}
+
+			expires := "Never"
+			if inv.MaxAge != 0 {
+				expires = fmt.Sprintf("<t:%v>", inv.CreatedAt.Time().Add(inv.MaxAge.Duration()).Unix())
+			}
+
+			e.Fields = append(e.Fields, discord.EmbedField{
+				Name: fmt.Sprintf("%v (%v)", inv.Code, name),
+				Value: fmt.Sprintf("**Channel:** %v\n**Uses:** %v\n**Created by:** %v\n**Expires:** %v",
+					inv.Channel.ID.Mention(), uses, inviter, expires),
+			})
+		}
+
+		embeds = append(embeds, e)
+	}
+
+	return bot.PaginateEmbeds(ctx, embeds)
+}
+
+func (bot *Bot) name(ctx *bcr.CommandContext) (err error) {
+	code := bot.inviteCode(ctx)
+	name := strings.TrimSpace(bot.Options(ctx).Find("name").String())
+
+	if name == "" {
+		return ctx.ReplyEphemeral("You must give a name.")
+	}
+	if len(name) > 100 {
+		return ctx.ReplyEphemeral("That name is too long, it can be at most 100 characters.")
+	}
+
+	_, ok, err := bot.guildInvite(ctx.Event.GuildID, code)
+	if err != nil {
+		log.Errorf("getting invite %q in %v: %v", code, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting invite"))
+	}
+	if !ok {
+		return ctx.ReplyEphemeral(fmt.Sprintf("Couldn't find an invite with the code **%v** in this server.", code))
+	}
+
+	err = bot.DB.NameInvite(ctx.Event.GuildID, code, name)
+	if err != nil {
+		log.Errorf("naming invite %q in %v: %v", code, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "naming invite"))
+	}
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("Named the invite **%v** %q.", code, name))
+}
+
+func (bot *Bot) unname(ctx *bcr.CommandContext) (err error) {
+	code := bot.inviteCode(ctx)
+
+	removed, err := bot.DB.DeleteInviteName(ctx.Event.GuildID, code)
+	if err != nil {
+		log.Errorf("removing name for invite %q in %v: %v", code, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "removing invite name"))
+	}
+
+	if !removed {
+		return ctx.ReplyEphemeral(fmt.Sprintf("The invite **%v** doesn't have a name.", code))
+	}
+	return ctx.ReplyEphemeral(fmt.Sprintf("Removed the name of the invite **%v**.", code))
+}
@@ -0,0 +1,21 @@
+package invites
+
+import (
+	"github.com/starshine-sys/catalogger/v2/bot"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+type Bot struct {
+	*bot.Bot
+}
+
+func Setup(root *bot.Bot) {
+	log.Debug("Adding invite commands")
+
+	bot := &Bot{Bot: root}
+
+	bot.Router.Command("invites/list").Exec(bot.list)
+	bot.Router.Command("invites/name").Exec(bot.name)
+	bot.Router.Command("invites/unname").Exec(bot.unname)
+	bot.Router.Command("invites/stats").Exec(bot.stats)
+}
@@ -0,0 +1,107 @@
+package invites
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// statsLimit is the number of invites and inviters shown in the stats command.
+const statsLimit = 15
+
+// statsPeriods maps the choices for the stats command's period option to how far back to count joins.
+var statsPeriods = map[string]struct {
+	name     string
+	duration time.Duration
+}{
+	"day":   {"the last day", 24 * time.Hour},
+	"week":  {"the last week", 7 * 24 * time.Hour},
+	"month": {"the last 30 days", 30 * 24 * time.Hour},
+	"year":  {"the last year", 365 * 24 * time.Hour},
+	"all":   {"all time", 0},
+}
+
+func (bot *Bot) stats(ctx *bcr.CommandContext) (err error) {
+	periodName := bot.Options(ctx).Find("period").String()
+	period, ok := statsPeriods[periodName]
+	if !ok {
+		period = statsPeriods["week"]
+	}
+
+	var since time.Time
+	if period.duration != 0 {
+		since = time.Now().Add(-period.duration)
+	}
+
+	invites, err := bot.DB.InviteJoinCounts(ctx.Event.GuildID, since, statsLimit)
+	if err != nil {
+		log.Errorf("getting invite join counts for %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting invite join counts"))
+	}
+
+	inviters, err := bot.DB.InviterJoinCounts(ctx.Event.GuildID, since, statsLimit)
+	if err != nil {
+		log.Errorf("getting inviter join counts for %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting inviter join counts"))
+	}
+
+	if len(invites) == 0 {
+		return ctx.ReplyEphemeral(fmt.Sprintf("No joins have been recorded in %v.", period.name))
+	}
+
+	names, err := bot.DB.InviteNames(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting invite names for %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting invite names"))
+	}
+
+	var b strings.Builder
+	for i, c := range invites {
+		fmt.Fprintf(&b, "%v. **%v**", i+1, c.Code)
+		if name, ok := names[c.Code]; ok {
+			fmt.Fprintf(&b, " (%v)", name)
+		}
+		fmt.Fprintf(&b, ": %v\n", joins(c.Joins))
+	}
+
+	e := discord.Embed{
+		Title:       "Invite statistics",
+		Description: fmt.Sprintf("Joins in %v", period.name),
+		Color:       common.ColourPurple,
+		Fields: []discord.EmbedField{{
+			Name:  "Invites",
+			Value: b.String(),
+		}},
+		Footer: &discord.EmbedFooter{
+			Text: "Only joins where the invite could be determined are counted",
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if len(inviters) > 0 {
+		b.Reset()
+		for i, c := range inviters {
+			fmt.Fprintf(&b, "%v. %v: %v\n", i+1, c.InviterID.Mention(), joins(c.Joins))
+		}
+
+		e.Fields = append(e.Fields, discord.EmbedField{
+			Name:  "Inviters",
+			Value: b.String(),
+		})
+	}
+
+	return ctx.ReplyEphemeral("", e)
+}
+
+func joins(n int) string {
+	if n == 1 {
+		return "1 join"
+	}
+	return fmt.Sprintf("%v joins", n)
+}
@@ -0,0 +1,132 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/jackc/pgx/v5"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// history shows every stored revision of a message.
+// The bot owner can view any message, moderators can only view messages in channels where they have Manage Messages.
+func (bot *Bot) history(ctx *bcr.CommandContext) (err error) {
+	sf, err := discord.ParseSnowflake(bot.Options(ctx).Find("id").String())
+	if err != nil {
+		return ctx.ReplyEphemeral("That isn't a valid message ID.")
+	}
+	id := discord.MessageID(sf)
+
+	m, err := bot.DB.GetMessage(id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// deleted messages keep their revisions for a while, so their history can still be shown
+		m, err = bot.DB.RevisedMessage(id)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ctx.ReplyEphemeral("There is no message with that ID stored.")
+		}
+
+		log.Errorf("getting message %v: %v", id, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting message"))
+	}
+
+	if ctx.User.ID != bot.Config.Bot.Owner {
+		if m.GuildID != ctx.Event.GuildID || ctx.Guild == nil || ctx.Member == nil {
+			return ctx.ReplyEphemeral("There is no message with that ID stored.")
+		}
+
+		// permissions are set on the parent channel for threads
+		ch, err := bot.Cabinet.RootChannel(context.Background(), m.ChannelID)
+		if err != nil {
+			log.Errorf("getting root channel for %v: %v", m.ChannelID, err)
+			return bot.ReportError(ctx, errors.Wrap(err, "getting channel"))
+		}
+
+		perms := discord.CalcOverwrites(*ctx.Guild, ch, *ctx.Member)
+		if !perms.Has(discord.PermissionManageMessages) {
+			return ctx.ReplyEphemeral("You need the Manage Messages permission in " + ch.Mention() + " to view this message's history.")
+		}
+	}
+
+	revisions, err := bot.DB.MessageRevisions(id)
+	if err != nil {
+		log.Errorf("getting revisions for message %v: %v", id, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting revisions"))
+	}
+
+	if len(revisions) == 0 {
+		return ctx.ReplyEphemeral("There is no edit history stored for that message.")
+	}
+
+	embed := discord.Embed{
+		Title:       fmt.Sprintf("History of message by %v", m.Username),
+		Description: fmt.Sprintf("https://discord.com/channels/%v/%v/%v", m.GuildID, m.ChannelID, m.ID),
+		Color:       common.ColourPurple,
+		Footer: &discord.EmbedFooter{
+			Text: "ID: " + m.ID.String(),
+		},
+		Timestamp: discord.Timestamp(m.ID.Time()),
+	}
+
+	// embeds can only have 25 fields, so only show the latest 24 revisions
+	start := 0
+	if len(revisions) > 24 {
+		start = len(revisions) - 24
+		embed.Description += fmt.Sprintf("\n\n%v older revision(s) are only included in the attached file.", start)
+	}
+
+	var (
+		file      strings.Builder
+		truncated bool
+	)
+	for i, r := range revisions {
+		name := fmt.Sprintf("Revision %v", i+1)
+		if i == 0 {
+			name = "Original"
+		}
+
+		fmt.Fprintf(&file, "--- %v (%v) ---\n%v\n\n", name, r.EditedAt.UTC().Format("2006-01-02 15:04:05"), r.Content)
+
+		if i < start {
+			continue
+		}
+
+		content := common.Truncate(r.Content, 1000)
+		if content != r.Content {
+			truncated = true
+		}
+
+		embed.Fields = append(embed.Fields, discord.EmbedField{
+			Name:  fmt.Sprintf("%v (<t:%v>)", name, r.EditedAt.Unix()),
+			Value: content,
+		})
+	}
+
+	data := api.InteractionResponseData{
+		Embeds: &[]discord.Embed{embed},
+		Flags:  discord.EphemeralMessage,
+	}
+
+	// attach the full history if it doesn't fit in the embed
+	if truncated || start > 0 || embed.Length() > 6000 {
+		data.Files = []sendpart.File{{
+			Name:   "history-" + m.ID.String() + ".txt",
+			Reader: strings.NewReader(file.String()),
+		}}
+		if embed.Length() > 6000 {
+			embed.Fields = nil
+			embed.Description += "\n\nThe history is too long to show here, see the attached file."
+			data.Embeds = &[]discord.Embed{embed}
+		}
+	}
+
+	return ctx.ReplyComplex(data)
+}
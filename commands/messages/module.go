@@ -0,0 +1,18 @@
+package messages
+
+import (
+	"github.com/starshine-sys/catalogger/v2/bot"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+type Bot struct {
+	*bot.Bot
+}
+
+func Setup(root *bot.Bot) {
+	log.Debug("Adding message commands")
+
+	bot := &Bot{Bot: root}
+
+	bot.Router.Command("message/history").Exec(bot.history)
+}
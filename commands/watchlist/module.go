@@ -0,0 +1,21 @@
+package watchlist
+
+import (
+	"github.com/starshine-sys/catalogger/v2/bot"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+type Bot struct {
+	*bot.Bot
+}
+
+func Setup(root *bot.Bot) {
+	log.Debug("Adding watchlist commands")
+
+	bot := &Bot{Bot: root}
+
+	bot.Router.Command("watchlist/add").Exec(bot.add)
+	bot.Router.Command("watchlist/remove").Exec(bot.remove)
+	bot.Router.Command("watchlist/list").Exec(bot.list)
+	bot.Router.Command("watchlist/show").Exec(bot.show)
+}
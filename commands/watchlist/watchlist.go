@@ -0,0 +1,170 @@
+package watchlist
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v5"
+	"github.com/starshine-sys/bcr/v2"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// entriesPerPage is the number of watchlist entries shown per page in the list command.
+const entriesPerPage = 10
+
+// userOption returns the user given in the "user" option.
+func (bot *Bot) userOption(ctx *bcr.CommandContext) (discord.UserID, bool) {
+	sf, err := bot.Options(ctx).Find("user").SnowflakeValue()
+	if err != nil || !sf.IsValid() {
+		return discord.NullUserID, false
+	}
+	return discord.UserID(sf), true
+}
+
+// userString returns the user's mention and tag, or just the mention if the user can't be fetched.
+func (bot *Bot) userString(guildID discord.GuildID, userID discord.UserID) string {
+	u, err := bot.GuildUser(guildID, userID)
+	if err != nil {
+		return userID.Mention()
+	}
+	return fmt.Sprintf("%v (%v)", u.Mention(), u.Tag())
+}
+
+// cachedUserString is like userString, but only uses cached users, so it never makes API requests.
+// This is used in lists, which are built before responding to the interaction.
+func (bot *Bot) cachedUserString(guildID discord.GuildID, userID discord.UserID) string {
+	u, ok := bot.CachedGuildUser(guildID, userID)
+	if !ok {
+		return userID.Mention()
+	}
+	return fmt.Sprintf("%v (%v)", u.Mention(), u.Tag())
+}
+
+func (bot *Bot) add(ctx *bcr.CommandContext) (err error) {
+	userID, ok := bot.userOption(ctx)
+	if !ok {
+		return ctx.ReplyEphemeral("That isn't a valid user.")
+	}
+
+	reason := bot.Options(ctx).Find("reason").String()
+	if utf8.RuneCountInString(reason) > 1000 {
+		return ctx.ReplyEphemeral("The reason is too long, it can be at most 1000 characters.")
+	}
+
+	err = bot.DB.AddToWatchlist(ctx.Event.GuildID, userID, ctx.User.ID, reason)
+	if err != nil {
+		log.Errorf("adding %v to watchlist in %v: %v", userID, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "adding to watchlist"))
+	}
+
+	// this can create a forum post, so don't hold up the response
+	go bot.SendDossier(ctx.Event.GuildID, userID, []discord.Embed{{
+		Title:       "Added to the watchlist",
+		Description: fmt.Sprintf("**Reason:** %v\n**Added by:** %v", reason, ctx.User.Mention()),
+		Color:       common.ColourOrange,
+		Footer:      &discord.EmbedFooter{Text: "User ID: " + userID.String()},
+		Timestamp:   discord.NowTimestamp(),
+	}})
+
+	return ctx.ReplyEphemeral(fmt.Sprintf("Added %v to the watchlist.", bot.userString(ctx.Event.GuildID, userID)))
+}
+
+func (bot *Bot) remove(ctx *bcr.CommandContext) (err error) {
+	userID, ok := bot.userOption(ctx)
+	if !ok {
+		return ctx.ReplyEphemeral("That isn't a valid user.")
+	}
+
+	removed, err := bot.DB.RemoveFromWatchlist(ctx.Event.GuildID, userID)
+	if err != nil {
+		log.Errorf("removing %v from watchlist in %v: %v", userID, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "removing from watchlist"))
+	}
+
+	if !removed {
+		return ctx.ReplyEphemeral(fmt.Sprintf("%v isn't on the watchlist.", bot.userString(ctx.Event.GuildID, userID)))
+	}
+
+	go bot.SendDossier(ctx.Event.GuildID, userID, []discord.Embed{{
+		Title:       "Removed from the watchlist",
+		Description: fmt.Sprintf("**Removed by:** %v", ctx.User.Mention()),
+		Color:       common.ColourGreen,
+		Footer:      &discord.EmbedFooter{Text: "User ID: " + userID.String()},
+		Timestamp:   discord.NowTimestamp(),
+	}})
+	return ctx.ReplyEphemeral(fmt.Sprintf("Removed %v from the watchlist.", bot.userString(ctx.Event.GuildID, userID)))
+}
+
+func (bot *Bot) show(ctx *bcr.CommandContext) (err error) {
+	userID, ok := bot.userOption(ctx)
+	if !ok {
+		return ctx.ReplyEphemeral("That isn't a valid user.")
+	}
+
+	e, err := bot.DB.WatchlistUser(ctx.Event.GuildID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ctx.ReplyEphemeral(fmt.Sprintf("%v isn't on the watchlist.", bot.userString(ctx.Event.GuildID, userID)))
+		}
+
+		log.Errorf("getting watchlist entry for %v in %v: %v", userID, ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting watchlist entry"))
+	}
+
+	embed := discord.Embed{
+		Title:       "Watchlist entry",
+		Description: bot.userString(ctx.Event.GuildID, e.UserID),
+		Color:       common.ColourOrange,
+		Fields: []discord.EmbedField{
+			{Name: "Reason", Value: e.Reason},
+			{Name: "Added by", Value: bot.userString(ctx.Event.GuildID, e.Moderator), Inline: true},
+			{Name: "Added", Value: fmt.Sprintf("<t:%v>", e.Added.Unix()), Inline: true},
+		},
+		Footer: &discord.EmbedFooter{
+			Text: "ID: " + e.UserID.String(),
+		},
+	}
+
+	if u, err := bot.GuildUser(ctx.Event.GuildID, e.UserID); err == nil {
+		embed.Thumbnail = &discord.EmbedThumbnail{URL: u.AvatarURL()}
+	}
+
+	return ctx.ReplyEphemeral("", embed)
+}
+
+func (bot *Bot) list(ctx *bcr.CommandContext) (err error) {
+	entries, err := bot.DB.Watchlist(ctx.Event.GuildID)
+	if err != nil {
+		log.Errorf("getting watchlist for %v: %v", ctx.Event.GuildID, err)
+		return bot.ReportError(ctx, errors.Wrap(err, "getting watchlist"))
+	}
+
+	if len(entries) == 0 {
+		return ctx.ReplyEphemeral("There are no users on the watchlist.")
+	}
+
+	var embeds []discord.Embed
+	for i := 0; i < len(entries); i += entriesPerPage {
+		end := i + entriesPerPage
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		var desc string
+		for _, e := range entries[i:end] {
+			desc += fmt.Sprintf("**%v**\n%v\nAdded by %v <t:%v:D>\n\n",
+				bot.cachedUserString(ctx.Event.GuildID, e.UserID), common.Truncate(e.Reason, 200), e.Moderator.Mention(), e.Added.Unix())
+		}
+
+		embeds = append(embeds, discord.Embed{
+			Title:       fmt.Sprintf("Watchlist (%v)", len(entries)),
+			Description: desc,
+			Color:       common.ColourOrange,
+		})
+	}
+
+	return bot.PaginateEmbeds(ctx, embeds)
+}
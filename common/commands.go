@@ -33,11 +33,426 @@ var Commands = []api.CreateCommandData{
 				OptionName:  "channels",
 				Description: "Configure logging channels",
 			},
+			&discord.SubcommandOption{
+				OptionName:  "unknown-messages",
+				Description: "Configure whether deletions of messages that aren't stored are logged",
+				Options: []discord.CommandOptionValue{
+					&discord.BooleanOption{
+						OptionName:  "enabled",
+						Description: "Whether to log these deletions (leave empty to show the current setting)",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "dossiers",
+				Description: "Configure the forum channel where each member gets a post with all events concerning them",
+				Options: []discord.CommandOptionValue{
+					&discord.ChannelOption{
+						OptionName:   "channel",
+						Description:  "The forum channel to post dossiers in (leave empty to show the current setting)",
+						ChannelTypes: []discord.ChannelType{discord.GuildForum},
+					},
+					&discord.BooleanOption{
+						OptionName:  "disable",
+						Description: "Stop posting member dossiers",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "ghost-pings",
+				Description: "Configure how soon a deleted message with pings has to be deleted to be logged as a ghost ping",
+				Options: []discord.CommandOptionValue{
+					&discord.IntegerOption{
+						OptionName:  "window",
+						Description: "The window in seconds (leave empty to show the current setting)",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "status",
+				Description: "Show whether logs are being delivered to each log channel",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "explain",
+				Description: "Explain where an event is logged, or why it isn't",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "event",
+						Description: "The event to explain",
+						Required:    true,
+						Choices:     Events,
+					},
+					&discord.ChannelOption{
+						OptionName:  "channel",
+						Description: "The channel the event happens in",
+					},
+					&discord.UserOption{
+						OptionName:  "user",
+						Description: "The user the event is about",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "banned-systems",
+				Description: "Configure banned PluralKit systems",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "add",
+						Description: "Ban a PluralKit system",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "system",
+								Description: "The system's ID",
+								Required:    true,
+							},
+						},
+					},
+					{
+						OptionName:  "remove",
+						Description: "Unban a PluralKit system",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "system",
+								Description: "The system's ID",
+								Required:    true,
+							},
+						},
+					},
+					{
+						OptionName:  "list",
+						Description: "List banned PluralKit systems",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "redirects",
+				Description: "Configure where message logs for specific channels are sent",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "add",
+						Description: "Redirect message logs for a channel or category",
+					},
+					{
+						OptionName:  "remove",
+						Description: "Remove a redirect",
+						Options: []discord.CommandOptionValue{
+							&discord.ChannelOption{
+								OptionName:  "source",
+								Description: "The redirected channel or category (leave empty to choose from a list)",
+							},
+						},
+					},
+					{
+						OptionName:  "list",
+						Description: "List redirects and the channels they apply to",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "ignore-rules",
+				Description: "Configure rules that stop specific events from being logged",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "add",
+						Description: "Stop an event from being logged for a user, channel, or role",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "event",
+								Description: "The event to ignore",
+								Required:    true,
+								Choices:     Events,
+							},
+							&discord.UserOption{
+								OptionName:  "user",
+								Description: "The user the event is about, such as the member whose nickname changed",
+							},
+							&discord.ChannelOption{
+								OptionName:  "channel",
+								Description: "The channel or category to ignore this event in",
+							},
+							&discord.RoleOption{
+								OptionName:  "role",
+								Description: "The role to ignore this event for (matches the role itself and members with it)",
+							},
+						},
+					},
+					{
+						OptionName:  "remove",
+						Description: "Remove an ignore rule",
+						Options: []discord.CommandOptionValue{
+							&discord.IntegerOption{
+								OptionName:  "id",
+								Description: "The rule's ID, as shown in the list",
+								Required:    true,
+							},
+						},
+					},
+					{
+						OptionName:  "list",
+						Description: "List ignore rules",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "alerts",
+				Description: "Configure roles that are pinged when specific events are logged",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "set",
+						Description: "Set or remove the role pinged for an event",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "event",
+								Description: "The event to ping a role for",
+								Required:    true,
+								Choices:     Events,
+							},
+							&discord.RoleOption{
+								OptionName:  "role",
+								Description: "The role to ping (leave empty to stop pinging a role)",
+							},
+						},
+					},
+					{
+						OptionName:  "list",
+						Description: "List roles pinged for events",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "batching",
+				Description: "Configure how events are grouped into messages before being logged",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "set",
+						Description: "Change how an event is batched (options that aren't given are left unchanged)",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "event",
+								Description: "The event to configure",
+								Required:    true,
+								Choices:     BatchingEvents,
+							},
+							&discord.BooleanOption{
+								OptionName:  "enabled",
+								Description: "Whether to batch the event at all",
+							},
+							&discord.IntegerOption{
+								OptionName:  "max-embeds",
+								Description: "The maximum number of embeds in a single message (1-10)",
+							},
+							&discord.IntegerOption{
+								OptionName:  "max-delay",
+								Description: "How long to wait for more events before sending, in seconds (1-300)",
+							},
+							&discord.IntegerOption{
+								OptionName:  "max-length",
+								Description: "The maximum total length of the embeds in a single message (1-6000)",
+							},
+						},
+					},
+					{
+						OptionName:  "reset",
+						Description: "Reset an event's batching to the default",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "event",
+								Description: "The event to reset",
+								Required:    true,
+								Choices:     BatchingEvents,
+							},
+						},
+					},
+					{
+						OptionName:  "list",
+						Description: "List this server's batching settings",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "links",
+				Description: "Send all logs from another server to a channel in this server",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "add",
+						Description: "Link another server's logs to a channel in this server",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "server",
+								Description: "The ID of the server to log (you need Manage Server there)",
+								Required:    true,
+							},
+							&discord.ChannelOption{
+								OptionName:   "channel",
+								Description:  "The channel to send its logs to",
+								Required:     true,
+								ChannelTypes: LogChannelTypes,
+							},
+						},
+					},
+					{
+						OptionName:  "remove",
+						Description: "Stop sending another server's logs here, or this server's logs elsewhere",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "server",
+								Description: "The ID of the server to unlink (leave empty to stop sending this server's logs)",
+							},
+						},
+					},
+					{
+						OptionName:  "list",
+						Description: "List servers linked to this server",
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "ignores",
+				Description: "Configure which channels and users are ignored in message logs",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "channels",
+						Description: "Ignore or unignore channels",
+					},
+					{
+						OptionName:  "users",
+						Description: "Ignore or unignore users and roles in all channels",
+					},
+					{
+						OptionName:  "channel-users",
+						Description: "Ignore or unignore users and roles in specific channels",
+					},
+					{
+						OptionName:  "list",
+						Description: "List ignored channels, users, and roles",
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:                     "message",
+		Description:              "View information about stored messages",
+		DefaultMemberPermissions: discord.NewPermissions(discord.PermissionManageMessages),
+		Options: discord.CommandOptions{
+			&discord.SubcommandOption{
+				OptionName:  "history",
+				Description: "Show every stored revision of a message",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "id",
+						Description: "The message's ID",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:                     "watchlist",
+		Description:              "Manage the server's watchlist",
+		DefaultMemberPermissions: discord.NewPermissions(discord.PermissionKickMembers),
+		Options: discord.CommandOptions{
+			&discord.SubcommandOption{
+				OptionName:  "add",
+				Description: "Add a user to the watchlist",
+				Options: []discord.CommandOptionValue{
+					&discord.UserOption{
+						OptionName:  "user",
+						Description: "The user to add",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "reason",
+						Description: "Why the user is being added",
+						Required:    true,
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "remove",
+				Description: "Remove a user from the watchlist",
+				Options: []discord.CommandOptionValue{
+					&discord.UserOption{
+						OptionName:  "user",
+						Description: "The user to remove",
+						Required:    true,
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "show",
+				Description: "Show a user's watchlist entry",
+				Options: []discord.CommandOptionValue{
+					&discord.UserOption{
+						OptionName:  "user",
+						Description: "The user to show",
+						Required:    true,
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "list",
+				Description: "List all users on the watchlist",
+			},
+		},
+	},
+	{
+		Name:                     "invites",
+		Description:              "Manage the server's invites",
+		DefaultMemberPermissions: discord.NewPermissions(discord.PermissionManageGuild),
+		Options: discord.CommandOptions{
+			&discord.SubcommandOption{
+				OptionName:  "list",
+				Description: "List the server's invites",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "name",
+				Description: "Give an invite a name",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "invite",
+						Description: "The invite code or link",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "name",
+						Description: "The invite's new name",
+						Required:    true,
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "unname",
+				Description: "Remove an invite's name",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "invite",
+						Description: "The invite code or link",
+						Required:    true,
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "stats",
+				Description: "Show which invites and inviters brought in the most members",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "period",
+						Description: "How far back to count joins (defaults to the last week)",
+						Choices: []discord.StringChoice{
+							{Name: "Last day", Value: "day"},
+							{Name: "Last week", Value: "week"},
+							{Name: "Last 30 days", Value: "month"},
+							{Name: "Last year", Value: "year"},
+							{Name: "All time", Value: "all"},
+						},
+					},
+				},
+			},
 		},
 	},
 }
 
-var events = []discord.StringChoice{
-	{Name: "Members joining", Value: "GUILD_MEMBER_ADD"},
-	{Name: "Members leaving", Value: "GUILD_MEMBER_REMOVE"},
-}
+// BatchingEvents are the choices for /config batching: every event, and all events at once.
+var BatchingEvents = append([]discord.StringChoice{{Name: EventName("ALL"), Value: "ALL"}}, Events...)
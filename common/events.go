@@ -0,0 +1,83 @@
+package common
+
+import "github.com/diamondburned/arikawa/v3/discord"
+
+// LogEvent is an event that can be logged.
+type LogEvent struct {
+	// Name is the event's name, as used by bot.Send.
+	Name string
+	// Key is the event's key, used in log channel settings, ignore rules, and commands.
+	// This is also the JSON name of the event's field in db.LogChannels.
+	Key string
+	// Title is the event's readable name.
+	Title string
+}
+
+// LogEvents are all events that can be logged, in the order they're shown to users.
+// Command choices, event keys, and log channel lookups are all derived from this.
+var LogEvents = []LogEvent{
+	{Name: "GuildUpdateEvent", Key: "GUILD_UPDATE", Title: "Server changes"},
+	{Name: "GuildEmojisUpdateEvent", Key: "GUILD_EMOJIS_UPDATE", Title: "Emote changes"},
+	{Name: "GuildRoleCreateEvent", Key: "GUILD_ROLE_CREATE", Title: "New roles"},
+	{Name: "GuildRoleUpdateEvent", Key: "GUILD_ROLE_UPDATE", Title: "Edited roles"},
+	{Name: "GuildRoleDeleteEvent", Key: "GUILD_ROLE_DELETE", Title: "Deleted roles"},
+	{Name: "ChannelCreateEvent", Key: "CHANNEL_CREATE", Title: "New channels"},
+	{Name: "ChannelUpdateEvent", Key: "CHANNEL_UPDATE", Title: "Edited channels"},
+	{Name: "ChannelDeleteEvent", Key: "CHANNEL_DELETE", Title: "Deleted channels"},
+	{Name: "GuildMemberAddEvent", Key: "GUILD_MEMBER_ADD", Title: "Members joining"},
+	{Name: "GuildMemberRemoveEvent", Key: "GUILD_MEMBER_REMOVE", Title: "Members leaving"},
+	{Name: "GuildMemberUpdateEvent", Key: "GUILD_MEMBER_UPDATE", Title: "Member role changes"},
+	{Name: "GuildKeyRoleUpdateEvent", Key: "GUILD_KEY_ROLE_UPDATE", Title: "Key role changes"},
+	{Name: "GuildMemberNickUpdateEvent", Key: "GUILD_MEMBER_NICK_UPDATE", Title: "Member name changes"},
+	{Name: "GuildMemberAvatarUpdateEvent", Key: "GUILD_MEMBER_AVATAR_UPDATE", Title: "Avatar changes"},
+	{Name: "GuildMemberKickEvent", Key: "GUILD_MEMBER_KICK", Title: "Kicks"},
+	{Name: "GuildBanAddEvent", Key: "GUILD_BAN_ADD", Title: "Bans"},
+	{Name: "GuildBanRemoveEvent", Key: "GUILD_BAN_REMOVE", Title: "Unbans"},
+	{Name: "InviteCreateEvent", Key: "INVITE_CREATE", Title: "New invites"},
+	{Name: "InviteDeleteEvent", Key: "INVITE_DELETE", Title: "Deleted invites"},
+	{Name: "MessageUpdateEvent", Key: "MESSAGE_UPDATE", Title: "Edited messages"},
+	{Name: "MessageDeleteEvent", Key: "MESSAGE_DELETE", Title: "Deleted messages"},
+	{Name: "MessageDeleteBulkEvent", Key: "MESSAGE_DELETE_BULK", Title: "Bulk deleted messages"},
+	{Name: "BannedSystemEvent", Key: "BANNED_SYSTEM", Title: "Banned systems"},
+	{Name: "GhostPingEvent", Key: "GHOST_PING", Title: "Ghost pings"},
+}
+
+// EventKeys maps event names (as used by bot.Send) to their keys.
+var EventKeys = func() map[string]string {
+	m := make(map[string]string, len(LogEvents))
+	for _, ev := range LogEvents {
+		m[ev.Name] = ev.Key
+	}
+	return m
+}()
+
+// Events are the command choices for all events.
+var Events = func() []discord.StringChoice {
+	choices := make([]discord.StringChoice, 0, len(LogEvents))
+	for _, ev := range LogEvents {
+		choices = append(choices, discord.StringChoice{Name: ev.Title, Value: ev.Key})
+	}
+	return choices
+}()
+
+// EventByKey returns the event with the given key.
+func EventByKey(key string) (LogEvent, bool) {
+	for _, ev := range LogEvents {
+		if ev.Key == key {
+			return ev, true
+		}
+	}
+	return LogEvent{}, false
+}
+
+// EventName returns the readable name for the given event key.
+func EventName(key string) string {
+	if key == "ALL" {
+		return "All events"
+	}
+
+	if ev, ok := EventByKey(key); ok {
+		return ev.Title
+	}
+	return key
+}
@@ -11,3 +11,14 @@ func Contains[T comparable](slice []T, v T) bool {
 	}
 	return false
 }
+
+// Toggle removes `v` from `slice` if it's in it, and adds it otherwise.
+// added is true if `v` was added.
+func Toggle[T comparable](slice []T, v T) (out []T, added bool) {
+	for i := range slice {
+		if slice[i] == v {
+			return append(slice[:i:i], slice[i+1:]...), false
+		}
+	}
+	return append(slice, v), true
+}
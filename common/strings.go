@@ -0,0 +1,12 @@
+package common
+
+import "unicode/utf8"
+
+// Truncate truncates s to at most length characters, adding an ellipsis if it was truncated.
+// Unlike slicing the string directly, this never splits a multi-byte character.
+func Truncate(s string, length int) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	return string([]rune(s)[:length]) + "…"
+}
@@ -0,0 +1,28 @@
+package common
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		length int
+		want   string
+	}{
+		{"empty", "", 5, ""},
+		{"short", "abc", 5, "abc"},
+		{"exact", "abcde", 5, "abcde"},
+		{"long", "abcdef", 5, "abcde…"},
+		{"multi-byte runes", "ééééé", 5, "ééééé"},
+		{"long multi-byte runes", "ééééééé", 5, "ééééé…"},
+		{"emoji", "👍👍👍", 2, "👍👍…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.s, tt.length); got != tt.want {
+				t.Errorf("Truncate(%q, %v) = %q, want %q", tt.s, tt.length, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,3 +5,10 @@ import "github.com/diamondburned/arikawa/v3/discord"
 func IsThread(ch discord.Channel) bool {
 	return ch.Type == discord.GuildNewsThread || ch.Type == discord.GuildPrivateThread || ch.Type == discord.GuildPublicThread
 }
+
+// LogChannelTypes are the channel types that logs can be sent to.
+// Threads and forum posts are logged to using their parent channel's webhook.
+var LogChannelTypes = []discord.ChannelType{
+	discord.GuildText, discord.GuildAnnouncement, discord.GuildVoice, discord.GuildStageVoice,
+	discord.GuildPublicThread, discord.GuildAnnouncementThread,
+}
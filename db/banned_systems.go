@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// BannedSystems returns the PluralKit system IDs banned in the given guild.
+func (db *DB) BannedSystems(guildID discord.GuildID) (systems []string, err error) {
+	err = db.QueryRow(context.Background(), "SELECT banned_systems FROM guilds WHERE id = $1", guildID).Scan(&systems)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return systems, nil
+}
+
+// IsSystemBanned returns true if the given PluralKit system is banned in the guild.
+func (db *DB) IsSystemBanned(guildID discord.GuildID, system string) (banned bool, err error) {
+	err = db.QueryRow(context.Background(),
+		"SELECT EXISTS(SELECT * FROM guilds WHERE id = $1 AND $2 = ANY(banned_systems))", guildID, system).Scan(&banned)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return banned, nil
+}
+
+// AddBannedSystem adds a PluralKit system to the guild's banned systems.
+// added is false if the system was already banned.
+func (db *DB) AddBannedSystem(guildID discord.GuildID, system string) (added bool, err error) {
+	ct, err := db.Exec(context.Background(),
+		"UPDATE guilds SET banned_systems = array_append(banned_systems, $2) WHERE id = $1 AND NOT ($2 = ANY(banned_systems))",
+		guildID, system)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
+
+// RemoveBannedSystem removes a PluralKit system from the guild's banned systems.
+// removed is false if the system wasn't banned.
+func (db *DB) RemoveBannedSystem(guildID discord.GuildID, system string) (removed bool, err error) {
+	ct, err := db.Exec(context.Background(),
+		"UPDATE guilds SET banned_systems = array_remove(banned_systems, $2) WHERE id = $1 AND $2 = ANY(banned_systems)",
+		guildID, system)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"reflect"
 
 	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/common"
 )
 
 // LogChannels is the map of log channels stored per server
@@ -31,14 +33,22 @@ type LogChannels struct {
 	MessageUpdate           discord.ChannelID `json:"MESSAGE_UPDATE"`
 	MessageDelete           discord.ChannelID `json:"MESSAGE_DELETE"`
 	MessageDeleteBulk       discord.ChannelID `json:"MESSAGE_DELETE_BULK"`
+	BannedSystem            discord.ChannelID `json:"BANNED_SYSTEM"`
+	GhostPing               discord.ChannelID `json:"GHOST_PING"`
+
+	// Extra are channels events are logged to in addition to their main log channel, keyed by event key.
+	// These are only used if the event's main log channel is set.
+	Extra map[string][]discord.ChannelID `json:"EXTRA,omitempty"`
 }
 
 type Redirects map[string]discord.ChannelID
 
 type Ignores struct {
-	GlobalChannels []discord.ChannelID         `json:"global_channels"`
-	GlobalUsers    []discord.UserID            `json:"global_users"`
-	PerChannel     map[string][]discord.UserID `json:"per_channel"`
+	GlobalChannels  []discord.ChannelID         `json:"global_channels"`
+	GlobalUsers     []discord.UserID            `json:"global_users"`
+	GlobalRoles     []discord.RoleID            `json:"global_roles"`
+	PerChannel      map[string][]discord.UserID `json:"per_channel"`
+	PerChannelRoles map[string][]discord.RoleID `json:"per_channel_roles"`
 }
 
 type Channels struct {
@@ -47,58 +57,53 @@ type Channels struct {
 	Ignores   Ignores
 }
 
-// For returns the channel ID for the given event.
-func (lc LogChannels) For(evName string) discord.ChannelID {
-	switch evName {
-	case "GuildUpdateEvent":
-		return lc.GuildUpdate
-	case "GuildEmojisUpdateEvent":
-		return lc.GuildEmojisUpdate
-	case "GuildRoleCreateEvent":
-		return lc.GuildRoleCreate
-	case "GuildRoleUpdateEvent":
-		return lc.GuildRoleUpdate
-	case "GuildRoleDeleteEvent":
-		return lc.GuildRoleDelete
-	case "ChannelCreateEvent":
-		return lc.ChannelCreate
-	case "ChannelUpdateEvent":
-		return lc.ChannelUpdate
-	case "ChannelDeleteEvent":
-		return lc.ChannelDelete
-	case "GuildMemberAddEvent":
-		return lc.GuildMemberAdd
-	case "GuildMemberRemoveEvent":
-		return lc.GuildMemberRemove
-	case "GuildMemberUpdateEvent":
-		return lc.GuildMemberUpdate
-	case "GuildKeyRoleUpdateEvent":
-		return lc.GuildKeyRoleUpdate
-	case "GuildMemberNickUpdateEvent":
-		return lc.GuildMemberNickUpdate
-	case "GuildMemberAvatarUpdateEvent":
-		return lc.GuildMemberAvatarUpdate
-	case "GuildMemberKickEvent":
-		return lc.GuildMemberKick
-	case "GuildBanAddEvent":
-		return lc.GuildBanAdd
-	case "GuildBanRemoveEvent":
-		return lc.GuildBanRemove
-	case "InviteCreateEvent":
-		return lc.InviteCreate
-	case "InviteDeleteEvent":
-		return lc.InviteDelete
-	case "MessageUpdateEvent":
-		return lc.MessageUpdate
-	case "MessageDeleteEvent":
-		return lc.MessageDelete
-	case "MessageDeleteBulkEvent":
-		return lc.MessageDeleteBulk
+// logChannelFields maps event keys to the index of their field in LogChannels, using the fields' JSON names.
+var logChannelFields = func() map[string]int {
+	m := map[string]int{}
+	t := reflect.TypeOf(LogChannels{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type == reflect.TypeOf(discord.ChannelID(0)) {
+			m[t.Field(i).Tag.Get("json")] = i
+		}
 	}
+	return m
+}()
 
+// Field returns a pointer to the log channel for the given event key, or nil if there's no such event.
+func (lc *LogChannels) Field(key string) *discord.ChannelID {
+	i, ok := logChannelFields[key]
+	if !ok {
+		return nil
+	}
+	return reflect.ValueOf(lc).Elem().Field(i).Addr().Interface().(*discord.ChannelID)
+}
+
+// ForKey returns the channel ID for the given event key.
+func (lc LogChannels) ForKey(key string) discord.ChannelID {
+	if id := lc.Field(key); id != nil {
+		return *id
+	}
 	return discord.NullChannelID
 }
 
+// For returns the channel ID for the given event name.
+func (lc LogChannels) For(evName string) discord.ChannelID {
+	key, ok := common.EventKeys[evName]
+	if !ok {
+		return discord.NullChannelID
+	}
+	return lc.ForKey(key)
+}
+
+// ExtraFor returns the additional channels for the given event name.
+func (lc LogChannels) ExtraFor(evName string) []discord.ChannelID {
+	key, ok := common.EventKeys[evName]
+	if !ok {
+		return nil
+	}
+	return lc.Extra[key]
+}
+
 func (db *DB) Channels(guildID discord.GuildID) (chs Channels, err error) {
 	sql, args, err := sq.Select("channels", "redirects", "ignores").From("guilds").Where("id = ?", guildID).ToSql()
 	if err != nil {
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// DeliveryStat is how reliably an event is delivered to a log channel.
+type DeliveryStat struct {
+	GuildID   discord.GuildID
+	ChannelID discord.ChannelID
+	// Event is the event key, or the event name for events that don't have a key.
+	Event string
+
+	Successes int64
+	Failures  int64
+	// ConsecutiveFailures is the number of failures since the last success.
+	ConsecutiveFailures int64
+
+	LastSuccess *time.Time
+	LastFailure *time.Time
+	// LastError is the error of the last failure.
+	LastError *string
+}
+
+// Failing returns true if the last delivery failed.
+func (s DeliveryStat) Failing() bool {
+	return s.ConsecutiveFailures > 0
+}
+
+// DeliveryCounts are deliveries of an event to a log channel that haven't been recorded yet.
+type DeliveryCounts struct {
+	Successes int64
+	Failures  int64
+	// ConsecutiveFailures is the number of failures after the last success, or all failures if none succeeded.
+	ConsecutiveFailures int64
+
+	// LastSuccess and LastFailure are only set if there were any successes or failures.
+	LastSuccess *time.Time
+	LastFailure *time.Time
+	LastError   *string
+}
+
+// Add adds a single delivery. If deliveryErr is nil, it succeeded.
+func (c *DeliveryCounts) Add(n int, deliveryErr error) {
+	now := time.Now().UTC()
+
+	if deliveryErr == nil {
+		c.Successes += int64(n)
+		c.ConsecutiveFailures = 0
+		c.LastSuccess = &now
+		return
+	}
+
+	msg := deliveryErr.Error()
+	c.Failures += int64(n)
+	c.ConsecutiveFailures += int64(n)
+	c.LastFailure = &now
+	c.LastError = &msg
+}
+
+// RecordDeliveries adds deliveries of an event to a log channel to its stats.
+// If any of the deliveries succeeded, the consecutive failures are reset to those after the last success.
+func (db *DB) RecordDeliveries(guildID discord.GuildID, channelID discord.ChannelID, event string, c DeliveryCounts) error {
+	sql, args, err := sq.Insert("delivery_stats").
+		Columns("guild_id", "channel_id", "event",
+			"successes", "failures", "consecutive_failures",
+			"last_success", "last_failure", "last_error").
+		Values(guildID, channelID, event,
+			c.Successes, c.Failures, c.ConsecutiveFailures,
+			c.LastSuccess, c.LastFailure, c.LastError).
+		Suffix(`ON CONFLICT (guild_id, channel_id, event) DO UPDATE SET
+			successes = delivery_stats.successes + EXCLUDED.successes,
+			failures = delivery_stats.failures + EXCLUDED.failures,
+			consecutive_failures = CASE WHEN EXCLUDED.last_success IS NULL
+				THEN delivery_stats.consecutive_failures + EXCLUDED.consecutive_failures
+				ELSE EXCLUDED.consecutive_failures END,
+			last_success = coalesce(EXCLUDED.last_success, delivery_stats.last_success),
+			last_failure = coalesce(EXCLUDED.last_failure, delivery_stats.last_failure),
+			last_error = coalesce(EXCLUDED.last_error, delivery_stats.last_error)`).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DeliveryStats returns the delivery stats for all of a guild's log channels.
+func (db *DB) DeliveryStats(guildID discord.GuildID) (stats []DeliveryStat, err error) {
+	sql, args, err := sq.Select("*").
+		From("delivery_stats").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		OrderBy("event", "channel_id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &stats, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return stats, nil
+}
+
+// FailingDeliveries returns all delivery stats that have failed at least minFailures times in a row,
+// with the last failure after since.
+func (db *DB) FailingDeliveries(since time.Time, minFailures int) (stats []DeliveryStat, err error) {
+	sql, args, err := sq.Select("*").
+		From("delivery_stats").
+		Where(squirrel.GtOrEq{"consecutive_failures": minFailures}).
+		Where(squirrel.Gt{"last_failure": since.UTC()}).
+		OrderBy("guild_id", "channel_id", "event").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &stats, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return stats, nil
+}
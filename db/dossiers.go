@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// DossierChannel returns the forum channel that member dossiers are posted in.
+// If dossiers are disabled, the returned ID is not valid.
+func (db *DB) DossierChannel(guildID discord.GuildID) (id discord.ChannelID, err error) {
+	sql, args, err := sq.Select("coalesce(dossier_channel, 0)").From("guilds").Where("id = ?", guildID).ToSql()
+	if err != nil {
+		return id, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&id)
+	if err != nil {
+		return id, errors.Wrap(err, "executing query")
+	}
+	return id, nil
+}
+
+// SetDossierChannel sets the forum channel that member dossiers are posted in.
+// If id is not valid, dossiers are disabled.
+func (db *DB) SetDossierChannel(guildID discord.GuildID, id discord.ChannelID) error {
+	var v *discord.ChannelID
+	if id.IsValid() {
+		v = &id
+	}
+
+	sql, args, err := sq.Update("guilds").
+		Set("dossier_channel", v).
+		Where("id = ?", guildID).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DossierThread returns the ID of the member's dossier thread.
+// If the member doesn't have one, returns pgx.ErrNoRows.
+func (db *DB) DossierThread(guildID discord.GuildID, userID discord.UserID) (id discord.ChannelID, err error) {
+	sql, args, err := sq.Select("thread_id").
+		From("dossier_threads").
+		Where(squirrel.Eq{"guild_id": guildID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return id, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&id)
+	if err != nil {
+		return id, errors.Wrap(err, "executing query")
+	}
+	return id, nil
+}
+
+// SetDossierThread sets the ID of the member's dossier thread.
+func (db *DB) SetDossierThread(guildID discord.GuildID, userID discord.UserID, threadID discord.ChannelID) error {
+	sql, args, err := sq.Insert("dossier_threads").
+		Columns("guild_id", "user_id", "thread_id").
+		Values(guildID, userID, threadID).
+		Suffix("ON CONFLICT (guild_id, user_id) DO UPDATE SET thread_id = EXCLUDED.thread_id").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// ClearDossierThreads forgets all of the guild's dossier threads, for when the dossier channel changes.
+func (db *DB) ClearDossierThreads(guildID discord.GuildID) error {
+	sql, args, err := sq.Delete("dossier_threads").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DeleteDossierThread forgets the member's dossier thread, for when it's been deleted.
+func (db *DB) DeleteDossierThread(guildID discord.GuildID, userID discord.UserID) error {
+	sql, args, err := sq.Delete("dossier_threads").
+		Where(squirrel.Eq{"guild_id": guildID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DeleteDossierThreadByID forgets the dossier thread with the given ID, for when it's been deleted.
+func (db *DB) DeleteDossierThreadByID(threadID discord.ChannelID) error {
+	sql, args, err := sq.Delete("dossier_threads").
+		Where(squirrel.Eq{"thread_id": threadID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
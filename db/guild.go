@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -24,3 +25,100 @@ func (db *DB) CreateGuild(id discord.GuildID) (alreadyExists bool, err error) {
 
 	return ct.RowsAffected() == 0, nil
 }
+
+// LogUnknownMessages returns whether the guild has opted in to logging deletions of messages that aren't stored.
+func (db *DB) LogUnknownMessages(id discord.GuildID) (enabled bool, err error) {
+	sql, args, err := sq.Select("log_unknown_messages").From("guilds").Where("id = ?", id).ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&enabled)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return enabled, nil
+}
+
+// SetLogUnknownMessages sets whether the guild logs deletions of messages that aren't stored.
+func (db *DB) SetLogUnknownMessages(id discord.GuildID, enabled bool) error {
+	sql, args, err := sq.Update("guilds").
+		Set("log_unknown_messages", enabled).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// AlertRoles is a map of event keys to the role that is pinged when that event is logged.
+type AlertRoles map[string]discord.RoleID
+
+// AlertRoles returns the guild's alert roles.
+func (db *DB) AlertRoles(id discord.GuildID) (roles AlertRoles, err error) {
+	sql, args, err := sq.Select("alert_roles").From("guilds").Where("id = ?", id).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&roles)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return roles, nil
+}
+
+// SetAlertRoles sets the guild's alert roles.
+func (db *DB) SetAlertRoles(id discord.GuildID, roles AlertRoles) error {
+	sql, args, err := sq.Update("guilds").
+		Set("alert_roles", roles).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// GhostPingWindow returns how long after being sent a deleted message that mentions someone is logged as a ghost ping.
+func (db *DB) GhostPingWindow(id discord.GuildID) (window time.Duration, err error) {
+	sql, args, err := sq.Select("ghost_ping_window").From("guilds").Where("id = ?", id).ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "building sql")
+	}
+
+	var seconds int
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&seconds)
+	if err != nil {
+		return 0, errors.Wrap(err, "executing query")
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// SetGhostPingWindow sets the guild's ghost ping window. It's stored with a precision of one second.
+func (db *DB) SetGhostPingWindow(id discord.GuildID, window time.Duration) error {
+	sql, args, err := sq.Update("guilds").
+		Set("ghost_ping_window", int(window/time.Second)).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
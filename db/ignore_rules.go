@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// IgnoreRule is a rule that stops a single event type from being logged for a user, channel, or role.
+// Exactly one of UserID, ChannelID, and RoleID is set.
+// UserID matches the user the event is about (for example, the member whose nickname changed),
+// not the user who caused it, as that's only known from the audit log.
+type IgnoreRule struct {
+	ID      int
+	GuildID discord.GuildID
+	Event   string
+
+	UserID    *discord.UserID
+	ChannelID *discord.ChannelID
+	RoleID    *discord.RoleID
+}
+
+// IgnoreRules returns all of the guild's ignore rules.
+func (db *DB) IgnoreRules(guildID discord.GuildID) (rs []IgnoreRule, err error) {
+	sql, args, err := sq.Select("*").
+		From("ignore_rules").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &rs, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return rs, nil
+}
+
+// AddIgnoreRule adds an ignore rule and returns its ID.
+func (db *DB) AddIgnoreRule(r IgnoreRule) (id int, err error) {
+	sql, args, err := sq.Insert("ignore_rules").
+		Columns("guild_id", "event", "user_id", "channel_id", "role_id").
+		Values(r.GuildID, r.Event, r.UserID, r.ChannelID, r.RoleID).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "executing query")
+	}
+	return id, nil
+}
+
+// RemoveIgnoreRule removes the ignore rule with the given ID.
+// removed is false if the guild has no rule with that ID.
+func (db *DB) RemoveIgnoreRule(guildID discord.GuildID, id int) (removed bool, err error) {
+	sql, args, err := sq.Delete("ignore_rules").
+		Where(squirrel.Eq{"guild_id": guildID, "id": id}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "building sql")
+	}
+
+	ct, err := db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
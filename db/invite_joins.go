@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// InviteCount is the number of joins through an invite.
+type InviteCount struct {
+	Code  string
+	Joins int
+}
+
+// InviterCount is the number of joins through all invites created by a user.
+type InviterCount struct {
+	InviterID discord.UserID
+	Joins     int
+}
+
+// AddInviteJoin records that a user joined the guild using the given invite.
+// inviterID may be invalid if the invite has no inviter.
+func (db *DB) AddInviteJoin(guildID discord.GuildID, code string, inviterID, userID discord.UserID) error {
+	var inviter *discord.UserID
+	if inviterID.IsValid() {
+		inviter = &inviterID
+	}
+
+	sql, args, err := sq.Insert("invite_joins").
+		Columns("guild_id", "code", "inviter_id", "user_id", "joined_at").
+		Values(guildID, code, inviter, userID, time.Now().UTC()).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// InviteJoinCounts returns the number of joins per invite since the given time, most joins first.
+// If since is zero, all joins are counted.
+func (db *DB) InviteJoinCounts(guildID discord.GuildID, since time.Time, limit uint64) (cs []InviteCount, err error) {
+	builder := sq.Select("code", "count(*) AS joins").
+		From("invite_joins").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		GroupBy("code").
+		OrderBy("joins DESC", "code").
+		Limit(limit)
+	if !since.IsZero() {
+		builder = builder.Where(squirrel.GtOrEq{"joined_at": since.UTC()})
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &cs, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return cs, nil
+}
+
+// InviterJoinCounts returns the number of joins per inviter since the given time, most joins first.
+// If since is zero, all joins are counted. Joins through invites without an inviter are not counted.
+func (db *DB) InviterJoinCounts(guildID discord.GuildID, since time.Time, limit uint64) (cs []InviterCount, err error) {
+	builder := sq.Select("inviter_id", "count(*) AS joins").
+		From("invite_joins").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		Where(squirrel.NotEq{"inviter_id": nil}).
+		GroupBy("inviter_id").
+		OrderBy("joins DESC", "inviter_id").
+		Limit(limit)
+	if !since.IsZero() {
+		builder = builder.Where(squirrel.GtOrEq{"joined_at": since.UTC()})
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &cs, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return cs, nil
+}
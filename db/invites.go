@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/jackc/pgx/v5"
+)
+
+// InviteNames returns a map of invite codes to names for the given guild.
+func (db *DB) InviteNames(guildID discord.GuildID) (map[string]string, error) {
+	sql, args, err := sq.Select("code", "name").
+		From("invites").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	rows, err := db.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var code, name string
+		err = rows.Scan(&code, &name)
+		if err != nil {
+			return nil, errors.Wrap(err, "scanning row")
+		}
+		names[code] = name
+	}
+	return names, errors.Wrap(rows.Err(), "reading rows")
+}
+
+// InviteName returns the name of the given invite, or an empty string if it has no name.
+func (db *DB) InviteName(guildID discord.GuildID, code string) (name string, err error) {
+	sql, args, err := sq.Select("name").
+		From("invites").
+		Where(squirrel.Eq{"guild_id": guildID, "code": code}).
+		ToSql()
+	if err != nil {
+		return "", errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "executing query")
+	}
+	return name, nil
+}
+
+// NameInvite sets the name of the given invite, overwriting any existing name.
+func (db *DB) NameInvite(guildID discord.GuildID, code, name string) error {
+	sql, args, err := sq.Insert("invites").
+		Columns("guild_id", "code", "name").
+		Values(guildID, code, name).
+		Suffix("ON CONFLICT (code) DO UPDATE SET guild_id = EXCLUDED.guild_id, name = EXCLUDED.name").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DeleteInviteName removes the name of the given invite.
+// removed is false if the invite had no name.
+func (db *DB) DeleteInviteName(guildID discord.GuildID, code string) (removed bool, err error) {
+	sql, args, err := sq.Delete("invites").
+		Where(squirrel.Eq{"guild_id": guildID, "code": code}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "building sql")
+	}
+
+	ct, err := db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// LogLink copies all of a server's logs to a channel in another server.
+type LogLink struct {
+	// SourceID is the server whose logs are copied.
+	SourceID discord.GuildID
+	// GuildID and ChannelID are where the logs are copied to.
+	GuildID   discord.GuildID
+	ChannelID discord.ChannelID
+	// CreatedBy is the user who linked the servers.
+	CreatedBy discord.UserID
+}
+
+// LogLink returns the link for the given source server.
+// If the server isn't linked, returns pgx.ErrNoRows.
+func (db *DB) LogLink(sourceID discord.GuildID) (l LogLink, err error) {
+	sql, args, err := sq.Select("*").
+		From("log_links").
+		Where(squirrel.Eq{"source_id": sourceID}).
+		ToSql()
+	if err != nil {
+		return l, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Get(context.Background(), db, &l, sql, args...)
+	if err != nil {
+		return l, errors.Wrap(err, "executing query")
+	}
+	return l, nil
+}
+
+// LogLinksTo returns all links with a destination in the given server.
+func (db *DB) LogLinksTo(guildID discord.GuildID) (ls []LogLink, err error) {
+	sql, args, err := sq.Select("*").
+		From("log_links").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		OrderBy("source_id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &ls, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return ls, nil
+}
+
+// SetLogLink links a source server to a destination channel, replacing any existing link.
+func (db *DB) SetLogLink(l LogLink) error {
+	sql, args, err := sq.Insert("log_links").
+		Columns("source_id", "guild_id", "channel_id", "created_by").
+		Values(l.SourceID, l.GuildID, l.ChannelID, l.CreatedBy).
+		Suffix("ON CONFLICT (source_id) DO UPDATE SET guild_id = EXCLUDED.guild_id, channel_id = EXCLUDED.channel_id, created_by = EXCLUDED.created_by").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DeleteLogLink removes the link for the given source server, if its destination is in the given server.
+// removed is false if there was no such link.
+func (db *DB) DeleteLogLink(guildID, sourceID discord.GuildID) (removed bool, err error) {
+	sql, args, err := sq.Delete("log_links").
+		Where(squirrel.Eq{"source_id": sourceID, "guild_id": guildID}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "building sql")
+	}
+
+	ct, err := db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
+
+// DeleteOutgoingLogLink removes the link for the given source server, wherever its destination is.
+// removed is false if the server wasn't linked.
+func (db *DB) DeleteOutgoingLogLink(sourceID discord.GuildID) (removed bool, err error) {
+	sql, args, err := sq.Delete("log_links").
+		Where(squirrel.Eq{"source_id": sourceID}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "building sql")
+	}
+
+	ct, err := db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
+
+// LogLinks returns all links.
+func (db *DB) LogLinks() (ls []LogLink, err error) {
+	sql, args, err := sq.Select("*").
+		From("log_links").
+		OrderBy("source_id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &ls, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return ls, nil
+}
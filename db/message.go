@@ -43,9 +43,33 @@ type Metadata struct {
 	Username string          `json:"username,omitempty"`
 	Avatar   string          `json:"avatar,omitempty"`
 	Embeds   []discord.Embed `json:"embeds,omitempty"`
+
+	Type                discord.MessageType `json:"type,omitempty"`
+	ReferencedMessageID *discord.MessageID  `json:"referenced_message_id,omitempty"`
+	Stickers            []string            `json:"stickers,omitempty"`
+	Attachments         []Attachment        `json:"attachments,omitempty"`
+
+	// Mentions and MentionRoles are the users and roles pinged by the message, for ghost ping detection.
+	Mentions     []discord.UserID `json:"mentions,omitempty"`
+	MentionRoles []discord.RoleID `json:"mention_roles,omitempty"`
+}
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	Filename string `json:"filename"`
+	URL      string `json:"url"`
+}
+
+// IsEmpty returns true if no metadata is set.
+func (md Metadata) IsEmpty() bool {
+	return md.UserID == nil && md.Username == "" && md.Avatar == "" &&
+		len(md.Embeds) == 0 && md.Type == discord.DefaultMessage &&
+		md.ReferencedMessageID == nil && len(md.Stickers) == 0 && len(md.Attachments) == 0 &&
+		len(md.Mentions) == 0 && len(md.MentionRoles) == 0
 }
 
-// InsertMessage inserts a message
+// InsertMessage inserts a message, or updates its content if it already exists.
+// The content is also stored as a new revision of the message.
 func (db *DB) InsertMessage(m Message) (err error) {
 	if m.Content == "" {
 		m.Content = "None"
@@ -75,12 +99,35 @@ func (db *DB) InsertMessage(m Message) (err error) {
 		metadata = &b
 	}
 
-	_, err = db.Exec(context.Background(), `insert into messages
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "beginning transaction")
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, `insert into messages
 (id, user_id, channel_id, guild_id, content, username, member, system, metadata) values
 ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 on conflict (id) do update
 set content = $5`, m.ID, m.UserID, m.ChannelID, m.GuildID, m.EncryptedContent, m.EncryptedUsername, m.Member, m.System, metadata)
-	return err
+	if err != nil {
+		return errors.Wrap(err, "inserting message")
+	}
+
+	// every version of the message's content is also stored as a revision
+	// the message's details are stored too, so its history can be viewed after it's deleted
+	_, err = tx.Exec(ctx, `insert into message_revisions
+(message_id, content, guild_id, channel_id, user_id, username) values
+($1, $2, $3, $4, $5, $6)`, m.ID, m.EncryptedContent, m.GuildID, m.ChannelID, m.UserID, m.EncryptedUsername)
+	if err != nil {
+		return errors.Wrap(err, "inserting revision")
+	}
+
+	return tx.Commit(ctx)
 }
 
 // UpdatePKInfo updates the PluralKit info for the given message, if it exists in the database.
@@ -173,7 +220,35 @@ func (db *DB) GetMessage(id discord.MessageID) (m *Message, err error) {
 	return
 }
 
-// DeleteMessage deletes a message from the database
+// UpdateMetadata replaces the stored metadata for a message.
+func (db *DB) UpdateMetadata(id discord.MessageID, md Metadata) error {
+	jsonb, err := json.Marshal(md)
+	if err != nil {
+		return errors.Wrap(err, "marshaling metadata")
+	}
+
+	b, err := Encrypt(jsonb, db.aesKey)
+	if err != nil {
+		return errors.Wrap(err, "encrypting metadata")
+	}
+
+	sql, args, err := sq.Update("messages").
+		Set("metadata", b).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// DeleteMessage deletes a message from the database.
+// Its revisions are kept until they're deleted by DeleteExpiredRevisions.
 func (db *DB) DeleteMessage(id discord.MessageID) error {
 	sql, args, err := sq.Delete("messages").
 		Where(squirrel.Eq{"id": id}).
@@ -186,6 +261,19 @@ func (db *DB) DeleteMessage(id discord.MessageID) error {
 	if err != nil {
 		return errors.Wrap(err, "executing query")
 	}
+
+	sql, args, err = sq.Update("message_revisions").
+		Set("deleted_at", squirrel.Expr("(current_timestamp at time zone 'utc')")).
+		Where(squirrel.Eq{"message_id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "marking revisions as deleted")
+	}
 	return nil
 }
 
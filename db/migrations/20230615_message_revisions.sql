@@ -0,0 +1,27 @@
+-- +migrate Up
+
+-- 2023-06-15: Store every revision of a message, not just the latest one.
+-- Revisions store the message's details too, so their history can still be viewed for a while after the message is deleted.
+create table message_revisions (
+    id          bigserial   primary key,
+    message_id  bigint      not null,
+    guild_id    bigint      not null,
+    channel_id  bigint      not null,
+    user_id     bigint      not null,
+    username    bytea       not null,
+
+    content     bytea       not null,
+    edited_at   timestamp   not null    default (current_timestamp at time zone 'utc'),
+    -- set when the message is deleted; the revisions are removed some time after that
+    deleted_at  timestamp
+);
+
+create index message_revisions_message_id_idx on message_revisions (message_id);
+create index message_revisions_deleted_at_idx on message_revisions (deleted_at);
+
+-- existing messages only have their latest content stored, which becomes their first revision.
+-- it's dated when the message was sent, which is taken from its ID.
+insert into message_revisions (message_id, guild_id, channel_id, user_id, username, content, edited_at)
+    select id, guild_id, channel_id, user_id, username, content,
+        to_timestamp(((id >> 22) + 1420070400000) / 1000.0) at time zone 'utc'
+    from messages;
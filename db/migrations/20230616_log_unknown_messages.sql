@@ -0,0 +1,4 @@
+-- +migrate Up
+
+-- 2023-06-16: Allow guilds to log deletions of messages that aren't stored
+alter table guilds add column log_unknown_messages boolean not null default false;
@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- 2023-06-17: Record which invite each member joined with
+create table invite_joins (
+    id          bigserial   primary key,
+    guild_id    bigint      not null,
+    code        text        not null,
+    inviter_id  bigint,
+    user_id     bigint      not null,
+    joined_at   timestamp   not null    default (current_timestamp at time zone 'utc')
+);
+
+create index invite_joins_guild_id_idx on invite_joins (guild_id, joined_at);
@@ -0,0 +1,16 @@
+-- +migrate Up
+
+-- 2023-06-18: Per-event ignore rules, targeting exactly one user, channel, or role
+create table ignore_rules (
+    id          serial  primary key,
+    guild_id    bigint  not null,
+    event       text    not null,
+
+    user_id     bigint,
+    channel_id  bigint,
+    role_id     bigint,
+
+    check (num_nonnulls(user_id, channel_id, role_id) = 1)
+);
+
+create index ignore_rules_guild_id_idx on ignore_rules (guild_id);
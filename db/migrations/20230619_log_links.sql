@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- 2023-06-19: Links that copy all of a server's logs to a channel in another server
+create table log_links (
+    source_id   bigint  primary key,
+    guild_id    bigint  not null,
+    channel_id  bigint  not null,
+    created_by  bigint  not null
+);
+
+create index log_links_guild_id_idx on log_links (guild_id);
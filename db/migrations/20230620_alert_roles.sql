@@ -0,0 +1,4 @@
+-- +migrate Up
+
+-- 2023-06-20: Roles that are pinged when specific events are logged
+alter table guilds add column alert_roles jsonb not null default '{}';
@@ -0,0 +1,12 @@
+-- +migrate Up
+
+-- 2023-06-21: Per-member forum posts that events concerning the member are mirrored to
+alter table guilds add column dossier_channel bigint;
+
+create table dossier_threads (
+    guild_id    bigint  not null,
+    user_id     bigint  not null,
+    thread_id   bigint  not null,
+
+    primary key (guild_id, user_id)
+);
@@ -0,0 +1,4 @@
+-- +migrate Up
+
+-- 2023-06-22: How long after being sent a deleted message with mentions is logged as a ghost ping, in seconds
+alter table guilds add column ghost_ping_window integer not null default 60;
@@ -0,0 +1,4 @@
+-- +migrate Up
+
+-- 2023-06-23: Per-event batching policies for log channels
+alter table guilds add column queue_policies jsonb not null default '{}';
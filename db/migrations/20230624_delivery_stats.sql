@@ -0,0 +1,16 @@
+-- +migrate Up
+
+-- 2023-06-24: Log delivery successes and failures per log channel and event
+create table delivery_stats (
+    guild_id             bigint not null,
+    channel_id           bigint not null,
+    event                text   not null,
+    successes            bigint not null default 0,
+    failures             bigint not null default 0,
+    consecutive_failures bigint not null default 0,
+    last_success         timestamp,
+    last_failure         timestamp,
+    last_error           text,
+
+    primary key (guild_id, channel_id, event)
+);
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// AllEvents is the QueuePolicies key for the policy that applies to every event in a guild.
+const AllEvents = "ALL"
+
+// QueuePolicy is how events are batched before being sent to a log channel.
+// Fields that aren't set are inherited from a less specific policy.
+type QueuePolicy struct {
+	// Enabled is whether events are batched at all. If false, they're sent immediately.
+	Enabled *bool `json:"enabled,omitempty" toml:"enabled"`
+	// MaxEmbeds is the maximum number of embeds sent in a single message.
+	MaxEmbeds int `json:"max_embeds,omitempty" toml:"max_embeds"`
+	// MaxDelay is how long embeds are held before being sent, if the queue doesn't fill up first.
+	MaxDelay time.Duration `json:"max_delay,omitempty" toml:"max_delay"`
+	// MaxLength is the maximum total length of the embeds sent in a single message.
+	MaxLength int `json:"max_length,omitempty" toml:"max_length"`
+}
+
+// Merge returns p, overridden by any fields set in other.
+func (p QueuePolicy) Merge(other QueuePolicy) QueuePolicy {
+	if other.Enabled != nil {
+		p.Enabled = other.Enabled
+	}
+	if other.MaxEmbeds != 0 {
+		p.MaxEmbeds = other.MaxEmbeds
+	}
+	if other.MaxDelay != 0 {
+		p.MaxDelay = other.MaxDelay
+	}
+	if other.MaxLength != 0 {
+		p.MaxLength = other.MaxLength
+	}
+	return p
+}
+
+// IsEmpty returns true if no fields are set.
+func (p QueuePolicy) IsEmpty() bool {
+	return p == QueuePolicy{}
+}
+
+// QueuePolicies is a map of event keys (or AllEvents) to a guild's batching policy for that event.
+type QueuePolicies map[string]QueuePolicy
+
+// QueuePolicies returns the guild's batching policies.
+func (db *DB) QueuePolicies(id discord.GuildID) (policies QueuePolicies, err error) {
+	sql, args, err := sq.Select("queue_policies").From("guilds").Where("id = ?", id).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&policies)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return policies, nil
+}
+
+// SetQueuePolicies sets the guild's batching policies.
+func (db *DB) SetQueuePolicies(id discord.GuildID, policies QueuePolicies) error {
+	sql, args, err := sq.Update("guilds").
+		Set("queue_policies", policies).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueuePolicyMerge(t *testing.T) {
+	yes, no := true, false
+
+	base := QueuePolicy{Enabled: &yes, MaxEmbeds: 5, MaxDelay: 5 * time.Second, MaxLength: 6000}
+
+	tests := []struct {
+		name  string
+		p     QueuePolicy
+		other QueuePolicy
+		want  QueuePolicy
+	}{
+		{
+			name: "empty override",
+			p:    base,
+			want: base,
+		},
+		{
+			name:  "empty base",
+			other: base,
+			want:  base,
+		},
+		{
+			name:  "override every field",
+			p:     base,
+			other: QueuePolicy{Enabled: &no, MaxEmbeds: 10, MaxDelay: time.Minute, MaxLength: 2000},
+			want:  QueuePolicy{Enabled: &no, MaxEmbeds: 10, MaxDelay: time.Minute, MaxLength: 2000},
+		},
+		{
+			name:  "override some fields",
+			p:     base,
+			other: QueuePolicy{MaxDelay: time.Minute},
+			want:  QueuePolicy{Enabled: &yes, MaxEmbeds: 5, MaxDelay: time.Minute, MaxLength: 6000},
+		},
+		{
+			name:  "disable",
+			p:     base,
+			other: QueuePolicy{Enabled: &no},
+			want:  QueuePolicy{Enabled: &no, MaxEmbeds: 5, MaxDelay: 5 * time.Second, MaxLength: 6000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.Merge(tt.other)
+			if got != tt.want {
+				t.Errorf("%+v.Merge(%+v) = %+v, want %+v", tt.p, tt.other, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueuePolicyIsEmpty(t *testing.T) {
+	yes := true
+
+	if !(QueuePolicy{}).IsEmpty() {
+		t.Errorf("zero policy isn't empty")
+	}
+	if (QueuePolicy{Enabled: &yes}).IsEmpty() {
+		t.Errorf("policy with Enabled set is empty")
+	}
+	if (QueuePolicy{MaxEmbeds: 1}).IsEmpty() {
+		t.Errorf("policy with MaxEmbeds set is empty")
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// Revision is a single version of a message's content.
+type Revision struct {
+	ID        int64
+	MessageID discord.MessageID
+
+	Content          string `db:"-"`
+	EncryptedContent []byte `db:"content"`
+
+	EditedAt time.Time
+}
+
+// MessageRevisions returns all revisions of the given message, oldest first.
+// The first revision is the message's original content, if it was stored when the message was sent.
+func (db *DB) MessageRevisions(id discord.MessageID) (rs []Revision, err error) {
+	sql, args, err := sq.Select("id", "message_id", "content", "edited_at").
+		From("message_revisions").
+		Where(squirrel.Eq{"message_id": id}).
+		OrderBy("edited_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &rs, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting from database")
+	}
+
+	for i := range rs {
+		out, err := Decrypt(rs[i].EncryptedContent, db.aesKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypting content")
+		}
+		rs[i].Content = string(out)
+	}
+
+	return rs, nil
+}
+
+// RevisedMessage returns the details of a message from its latest revision, for messages that have been deleted.
+// Only ID, UserID, ChannelID, GuildID, and Username are set.
+// If no revisions are stored for the message, returns pgx.ErrNoRows.
+func (db *DB) RevisedMessage(id discord.MessageID) (m *Message, err error) {
+	m = &Message{}
+
+	sql, args, err := sq.Select("message_id", "user_id", "channel_id", "guild_id", "username").
+		From("message_revisions").
+		Where(squirrel.Eq{"message_id": id}).
+		OrderBy("edited_at DESC", "id DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return m, errors.Wrap(err, "building sql")
+	}
+
+	err = db.QueryRow(context.Background(), sql, args...).Scan(&m.ID, &m.UserID, &m.ChannelID, &m.GuildID, &m.EncryptedUsername)
+	if err != nil {
+		return m, errors.Wrap(err, "executing query")
+	}
+
+	username, err := Decrypt(m.EncryptedUsername, db.aesKey)
+	if err != nil {
+		return m, errors.Wrap(err, "decrypting username")
+	}
+	m.Username = string(username)
+
+	return m, nil
+}
+
+// DeleteExpiredRevisions deletes the revisions of all messages that were deleted before the given time.
+func (db *DB) DeleteExpiredRevisions(before time.Time) (n int64, err error) {
+	sql, args, err := sq.Delete("message_revisions").
+		Where(squirrel.Lt{"deleted_at": before.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "building sql")
+	}
+
+	ct, err := db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected(), nil
+}
+
+// RevisionCount returns the number of revisions stored for the given message.
+func (db *DB) RevisionCount(id discord.MessageID) (count int, err error) {
+	err = db.QueryRow(context.Background(), "SELECT COUNT(*) FROM message_revisions WHERE message_id = $1", id).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "executing query")
+	}
+	return count, nil
+}
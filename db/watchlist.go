@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// WatchlistEntry is a user on a guild's watchlist.
+type WatchlistEntry struct {
+	GuildID discord.GuildID
+	UserID  discord.UserID
+
+	Moderator discord.UserID
+	Added     time.Time
+	Reason    string
+}
+
+// Watchlist returns the guild's watchlist, most recently added first.
+func (db *DB) Watchlist(guildID discord.GuildID) (es []WatchlistEntry, err error) {
+	sql, args, err := sq.Select("*").
+		From("watchlist").
+		Where(squirrel.Eq{"guild_id": guildID}).
+		OrderBy("added DESC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Select(context.Background(), db, &es, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing query")
+	}
+	return es, nil
+}
+
+// WatchlistUser returns the watchlist entry for the given user.
+// If the user is not on the watchlist, the error will be pgx.ErrNoRows.
+func (db *DB) WatchlistUser(guildID discord.GuildID, userID discord.UserID) (e WatchlistEntry, err error) {
+	sql, args, err := sq.Select("*").
+		From("watchlist").
+		Where(squirrel.Eq{"guild_id": guildID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return e, errors.Wrap(err, "building sql")
+	}
+
+	err = pgxscan.Get(context.Background(), db, &e, sql, args...)
+	if err != nil {
+		return e, errors.Wrap(err, "executing query")
+	}
+	return e, nil
+}
+
+// AddToWatchlist adds a user to the guild's watchlist.
+// If the user is already on the watchlist, the moderator, time, and reason are updated.
+func (db *DB) AddToWatchlist(guildID discord.GuildID, userID, moderator discord.UserID, reason string) error {
+	sql, args, err := sq.Insert("watchlist").
+		Columns("guild_id", "user_id", "moderator", "added", "reason").
+		Values(guildID, userID, moderator, time.Now().UTC(), reason).
+		Suffix("ON CONFLICT (guild_id, user_id) DO UPDATE SET moderator = EXCLUDED.moderator, added = EXCLUDED.added, reason = EXCLUDED.reason").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "building sql")
+	}
+
+	_, err = db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "executing query")
+	}
+	return nil
+}
+
+// RemoveFromWatchlist removes a user from the guild's watchlist.
+// removed is false if the user wasn't on the watchlist.
+func (db *DB) RemoveFromWatchlist(guildID discord.GuildID, userID discord.UserID) (removed bool, err error) {
+	sql, args, err := sq.Delete("watchlist").
+		Where(squirrel.Eq{"guild_id": guildID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "building sql")
+	}
+
+	ct, err := db.Exec(context.Background(), sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "executing query")
+	}
+	return ct.RowsAffected() != 0, nil
+}
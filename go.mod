@@ -20,6 +20,7 @@ require (
 	github.com/starshine-sys/pkgo/v2 v2.1.0
 	github.com/urfave/cli/v2 v2.16.3
 	go.uber.org/zap v1.23.0
+	golang.org/x/time v0.0.0-20220922220347-f3bd1da661af
 )
 
 require (
@@ -52,5 +53,4 @@ require (
 	golang.org/x/sync v0.1.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
 	golang.org/x/text v0.7.0 // indirect
-	golang.org/x/time v0.0.0-20220922220347-f3bd1da661af // indirect
 )
@@ -69,6 +69,8 @@ func (bot *Bot) inviteCreate(ev *gateway.InviteCreateEvent) {
 		Timestamp: discord.NowTimestamp(),
 	}
 
+	bot.addNameField(&e, ev.GuildID, ev.Code)
+
 	bot.Send(ev.GuildID, ev, SendData{
 		Embeds: []discord.Embed{e},
 	})
@@ -12,8 +12,13 @@ import (
 )
 
 func (bot *Bot) inviteDelete(ev *gateway.InviteDeleteEvent) {
-	// update the cached invites when we're done handling this event
+	// update the cached invites and remove the invite's name when we're done handling this event
 	defer func() {
+		_, err := bot.DB.DeleteInviteName(ev.GuildID, ev.Code)
+		if err != nil {
+			log.Errorf("deleting name for invite %q in %v: %v", ev.Code, ev.GuildID, err)
+		}
+
 		inv, err := bot.Router.Rest.GuildInvites(ev.GuildID)
 		if err != nil {
 			log.Errorf("getting invites for %v: %v", ev.GuildID, err)
@@ -92,6 +97,8 @@ func (bot *Bot) inviteDelete(ev *gateway.InviteDeleteEvent) {
 		Timestamp: discord.NowTimestamp(),
 	}
 
+	bot.addNameField(&e, ev.GuildID, ev.Code)
+
 	bot.Send(ev.GuildID, ev, SendData{
 		Embeds: []discord.Embed{e},
 	})
@@ -0,0 +1,23 @@
+package invites
+
+import (
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// addNameField adds the invite's name to the embed, if it has one.
+func (bot *Bot) addNameField(e *discord.Embed, guildID discord.GuildID, code string) {
+	name, err := bot.DB.InviteName(guildID, code)
+	if err != nil {
+		log.Errorf("getting name for invite %q in %v: %v", code, guildID, err)
+		return
+	}
+	if name == "" {
+		return
+	}
+
+	e.Fields = append([]discord.EmbedField{{
+		Name:  "Name",
+		Value: name,
+	}}, e.Fields...)
+}
@@ -0,0 +1,40 @@
+package members
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common"
+)
+
+func (bot *Bot) banAdd(ev *gateway.GuildBanAddEvent) {
+	if !bot.ShouldLog() {
+		return
+	}
+
+	e := discord.Embed{
+		Author: &discord.EmbedAuthor{
+			Name: ev.User.Tag(),
+			Icon: ev.User.AvatarURL(),
+		},
+		Title:       "User banned",
+		Description: fmt.Sprintf("%v (%v)", ev.User.Mention(), ev.User.Tag()),
+		Color:       common.ColourRed,
+		Thumbnail: &discord.EmbedThumbnail{
+			URL: ev.User.AvatarURL(),
+		},
+		Footer: &discord.EmbedFooter{
+			Text: "ID: " + ev.User.ID.String(),
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if field, ok := bot.WatchlistField(ev.GuildID, ev.User.ID); ok {
+		e.Fields = append(e.Fields, field)
+	}
+
+	bot.Send(ev.GuildID, ev, SendData{
+		Embeds: []discord.Embed{e},
+	})
+}
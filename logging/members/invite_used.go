@@ -0,0 +1,140 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// inviteRefreshDelay is how long joins are collected before a guild's invites are fetched.
+// Every member joining in that time shares a single request, rather than each fetching all of the guild's invites.
+const inviteRefreshDelay = 2 * time.Second
+
+// inviteRefresh is a pending refresh of a guild's invites.
+type inviteRefresh struct {
+	// joins is how many members joined while the refresh was pending. It's only used while inviteRefreshesMu is held.
+	joins int
+
+	// done is closed once inv and ok are set.
+	done chan struct{}
+	inv  discord.Invite
+	ok   bool
+}
+
+// usedInvite finds the invite a member just joined with, by comparing the cached invites to the guild's current invites.
+// The cached invites are then updated. ok is false if the invite couldn't be determined.
+// Members who join within inviteRefreshDelay of each other share a single refresh, and are attributed the same invite.
+func (bot *Bot) usedInvite(guildID discord.GuildID) (inv discord.Invite, ok bool) {
+	bot.inviteRefreshesMu.Lock()
+	r, exists := bot.inviteRefreshes[guildID]
+	if !exists {
+		r = &inviteRefresh{done: make(chan struct{})}
+		bot.inviteRefreshes[guildID] = r
+
+		time.AfterFunc(inviteRefreshDelay, func() {
+			// members joining from now on wait for the next refresh, as their invite uses might not be counted in this one
+			bot.inviteRefreshesMu.Lock()
+			delete(bot.inviteRefreshes, guildID)
+			joins := r.joins
+			bot.inviteRefreshesMu.Unlock()
+
+			r.inv, r.ok = bot.refreshInvites(guildID, joins)
+			close(r.done)
+		})
+	}
+	r.joins++
+	bot.inviteRefreshesMu.Unlock()
+
+	<-r.done
+	return r.inv, r.ok
+}
+
+// refreshInvites fetches the guild's invites, updates the cached invites,
+// and returns the invite that the given number of members joined with.
+func (bot *Bot) refreshInvites(guildID discord.GuildID, joins int) (inv discord.Invite, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cached, err := bot.Cabinet.Invites(ctx, guildID)
+	if err != nil {
+		log.Errorf("getting cached invites for %v: %v", guildID, err)
+		return inv, false
+	}
+
+	current, err := bot.Router.Rest.GuildInvites(guildID)
+	if err != nil {
+		log.Errorf("getting invites for %v: %v", guildID, err)
+		return inv, false
+	}
+
+	err = bot.Cabinet.SetInvites(ctx, guildID, current)
+	if err != nil {
+		log.Errorf("setting invites for %v: %v", guildID, err)
+	}
+
+	inv, ok = matchUsedInvite(cached, current, joins)
+	if !ok {
+		log.Debugf("couldn't determine the invite used by %v join(s) in %v", joins, guildID)
+	}
+	return inv, ok
+}
+
+// matchUsedInvite returns the invite that was used by the given number of joins, by comparing the invites before and after.
+// ok is false if more than one invite was used, as there's no way to tell which member used which invite.
+func matchUsedInvite(before, after []discord.Invite, joins int) (inv discord.Invite, ok bool) {
+	afterByCode := make(map[string]discord.Invite, len(after))
+	for _, i := range after {
+		afterByCode[i.Code] = i
+	}
+
+	var candidates []discord.Invite
+	for _, old := range before {
+		if i, exists := afterByCode[old.Code]; exists {
+			if i.Uses > old.Uses {
+				candidates = append(candidates, i)
+			}
+			continue
+		}
+
+		// the invite was deleted, possibly because it reached its maximum uses
+		if old.MaxUses != 0 && old.Uses < old.MaxUses && old.MaxUses-old.Uses <= joins {
+			old.Uses = old.MaxUses
+			candidates = append(candidates, old)
+		}
+	}
+
+	if len(candidates) != 1 {
+		return inv, false
+	}
+	return candidates[0], true
+}
+
+// inviteField returns an embed field describing the invite a member joined with.
+func (bot *Bot) inviteField(guildID discord.GuildID, inv discord.Invite) discord.EmbedField {
+	value := "**Code:** " + inv.Code
+
+	name, err := bot.DB.InviteName(guildID, inv.Code)
+	if err != nil {
+		log.Errorf("getting name for invite %q in %v: %v", inv.Code, guildID, err)
+	} else if name != "" {
+		value += "\n**Name:** " + name
+	}
+
+	if inv.Inviter != nil {
+		value += fmt.Sprintf("\n**Created by:** %v (%v)", inv.Inviter.Mention(), inv.Inviter.Tag())
+	}
+
+	uses := fmt.Sprint(inv.Uses)
+	if inv.MaxUses != 0 {
+		uses += fmt.Sprintf("/%v", inv.MaxUses)
+	}
+	value += "\n**Uses:** " + uses
+
+	return discord.EmbedField{
+		Name:  "Invite used",
+		Value: value,
+	}
+}
@@ -0,0 +1,92 @@
+package members
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestMatchUsedInvite(t *testing.T) {
+	invite := func(code string, uses, maxUses int) discord.Invite {
+		return discord.Invite{
+			Code:           code,
+			InviteMetadata: discord.InviteMetadata{Uses: uses, MaxUses: maxUses},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		before []discord.Invite
+		after  []discord.Invite
+		joins  int
+		want   string
+		ok     bool
+	}{
+		{
+			name:  "no invites",
+			joins: 1,
+		},
+		{
+			name:   "one invite used",
+			before: []discord.Invite{invite("a", 1, 0), invite("b", 3, 0)},
+			after:  []discord.Invite{invite("a", 2, 0), invite("b", 3, 0)},
+			joins:  1,
+			want:   "a",
+			ok:     true,
+		},
+		{
+			name:   "one invite used by several joins",
+			before: []discord.Invite{invite("a", 1, 0), invite("b", 3, 0)},
+			after:  []discord.Invite{invite("a", 1, 0), invite("b", 6, 0)},
+			joins:  3,
+			want:   "b",
+			ok:     true,
+		},
+		{
+			name:   "several invites used",
+			before: []discord.Invite{invite("a", 1, 0), invite("b", 3, 0)},
+			after:  []discord.Invite{invite("a", 2, 0), invite("b", 4, 0)},
+			joins:  2,
+		},
+		{
+			name:   "no uses changed",
+			before: []discord.Invite{invite("a", 1, 0)},
+			after:  []discord.Invite{invite("a", 1, 0)},
+			joins:  1,
+		},
+		{
+			name:   "invite reached its maximum uses",
+			before: []discord.Invite{invite("a", 4, 5), invite("b", 3, 0)},
+			after:  []discord.Invite{invite("b", 3, 0)},
+			joins:  1,
+			want:   "a",
+			ok:     true,
+		},
+		{
+			name:   "invite reached its maximum uses with several joins",
+			before: []discord.Invite{invite("a", 3, 5)},
+			joins:  2,
+			want:   "a",
+			ok:     true,
+		},
+		{
+			name:   "invite deleted before reaching its maximum uses",
+			before: []discord.Invite{invite("a", 1, 5)},
+			joins:  1,
+		},
+		{
+			name:   "unlimited invite deleted",
+			before: []discord.Invite{invite("a", 1, 0)},
+			joins:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, ok := matchUsedInvite(tt.before, tt.after, tt.joins)
+			if ok != tt.ok || inv.Code != tt.want {
+				t.Errorf("matchUsedInvite() = %q, %v, want %q, %v", inv.Code, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/duration"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+func (bot *Bot) memberAdd(ev *gateway.GuildMemberAddEvent) {
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := bot.Cabinet.SetMember(ctx, ev.GuildID, ev.Member)
+		if err != nil {
+			log.Errorf("setting member %v in %v: %v", ev.User.ID, ev.GuildID, err)
+		}
+	}()
+
+	// record which invite the member used, for invite statistics
+	inv, invFound := bot.usedInvite(ev.GuildID)
+	if invFound {
+		var inviterID discord.UserID
+		if inv.Inviter != nil {
+			inviterID = inv.Inviter.ID
+		}
+
+		err := bot.DB.AddInviteJoin(ev.GuildID, inv.Code, inviterID, ev.User.ID)
+		if err != nil {
+			log.Errorf("recording invite join for %v in %v: %v", ev.User.ID, ev.GuildID, err)
+		}
+	}
+
+	if !bot.ShouldLog() {
+		return
+	}
+
+	e := discord.Embed{
+		Author: &discord.EmbedAuthor{
+			Name: ev.User.Tag(),
+			Icon: ev.User.AvatarURL(),
+		},
+		Title:       "Member joined",
+		Description: fmt.Sprintf("%v (%v)", ev.User.Mention(), ev.User.Tag()),
+		Color:       common.ColourGreen,
+		Thumbnail: &discord.EmbedThumbnail{
+			URL: ev.User.AvatarURL(),
+		},
+		Fields: []discord.EmbedField{{
+			Name:  "Account created",
+			Value: fmt.Sprintf("<t:%v>\n%v", ev.User.ID.Time().Unix(), duration.FormatTime(ev.User.ID.Time())),
+		}},
+		Footer: &discord.EmbedFooter{
+			Text: "ID: " + ev.User.ID.String(),
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if invFound {
+		e.Fields = append(e.Fields, bot.inviteField(ev.GuildID, inv))
+	}
+
+	if field, ok := bot.WatchlistField(ev.GuildID, ev.User.ID); ok {
+		e.Color = common.ColourOrange
+		e.Fields = append(e.Fields, field)
+	}
+
+	bot.Send(ev.GuildID, ev, SendData{
+		Embeds: []discord.Embed{e},
+	})
+}
@@ -0,0 +1,91 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/duration"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+func (bot *Bot) memberRemove(ev *gateway.GuildMemberRemoveEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// get the member before they're removed from the cache, if possible
+	m, err := bot.Cabinet.Member(ctx, ev.GuildID, ev.User.ID)
+	if err != nil {
+		log.Debugf("member %v in %v was not cached: %v", ev.User.ID, ev.GuildID, err)
+	}
+	cached := err == nil
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := bot.Cabinet.DeleteMember(ctx, ev.GuildID, ev.User.ID)
+		if err != nil {
+			log.Errorf("deleting member %v in %v: %v", ev.User.ID, ev.GuildID, err)
+		}
+	}()
+
+	if !bot.ShouldLog() {
+		return
+	}
+
+	e := discord.Embed{
+		Author: &discord.EmbedAuthor{
+			Name: ev.User.Tag(),
+			Icon: ev.User.AvatarURL(),
+		},
+		Title:       "Member left",
+		Description: fmt.Sprintf("%v (%v)", ev.User.Mention(), ev.User.Tag()),
+		Color:       common.ColourRed,
+		Thumbnail: &discord.EmbedThumbnail{
+			URL: ev.User.AvatarURL(),
+		},
+		Footer: &discord.EmbedFooter{
+			Text: "ID: " + ev.User.ID.String(),
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	if cached {
+		if m.Joined.IsValid() {
+			e.Fields = append(e.Fields, discord.EmbedField{
+				Name:  "Joined",
+				Value: fmt.Sprintf("<t:%v>\n%v", m.Joined.Time().Unix(), duration.FormatTime(m.Joined.Time())),
+			})
+		}
+
+		if len(m.RoleIDs) > 0 {
+			var roles []string
+			for _, id := range m.RoleIDs {
+				roles = append(roles, id.Mention())
+			}
+
+			value := strings.Join(roles, ", ")
+			if len(value) > 1000 {
+				value = value[:1000] + "…"
+			}
+			e.Fields = append(e.Fields, discord.EmbedField{
+				Name:  "Roles",
+				Value: value,
+			})
+		}
+	}
+
+	if field, ok := bot.WatchlistField(ev.GuildID, ev.User.ID); ok {
+		e.Color = common.ColourOrange
+		e.Fields = append(e.Fields, field)
+	}
+
+	bot.Send(ev.GuildID, ev, SendData{
+		Embeds: []discord.Embed{e},
+	})
+}
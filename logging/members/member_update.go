@@ -0,0 +1,30 @@
+package members
+
+import (
+	"context"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// memberUpdate keeps cached members up to date, so that role-based ignores use the member's current roles.
+func (bot *Bot) memberUpdate(ev *gateway.GuildMemberUpdateEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	m, err := bot.Cabinet.Member(ctx, ev.GuildID, ev.User.ID)
+	if err != nil {
+		log.Debugf("member %v in %v was not cached: %v", ev.User.ID, ev.GuildID, err)
+	}
+
+	m.User = ev.User
+	m.Nick = ev.Nick
+	m.Avatar = ev.Avatar
+	m.RoleIDs = ev.RoleIDs
+
+	err = bot.Cabinet.SetMember(ctx, ev.GuildID, m)
+	if err != nil {
+		log.Errorf("setting member %v in %v: %v", ev.User.ID, ev.GuildID, err)
+	}
+}
@@ -0,0 +1,39 @@
+package members
+
+import (
+	"sync"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/bot"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+type SendData = bot.SendData
+
+type Bot struct {
+	*bot.Bot
+
+	// inviteRefreshes are the pending refreshes of each guild's invites, shared by members joining at the same time.
+	inviteRefreshesMu sync.Mutex
+	inviteRefreshes   map[discord.GuildID]*inviteRefresh
+}
+
+func Setup(root *bot.Bot) {
+	log.Debug("Adding members handlers")
+
+	bot := &Bot{
+		Bot:             root,
+		inviteRefreshes: make(map[discord.GuildID]*inviteRefresh),
+	}
+
+	bot.AddHandler(
+		// member join logs
+		bot.memberAdd,
+		// member leave logs
+		bot.memberRemove,
+		// member cache updates
+		bot.memberUpdate,
+		// ban logs
+		bot.banAdd,
+	)
+}
@@ -0,0 +1,49 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// checkBannedSystem sends an alert if the given PluralKit system is banned in the guild.
+// link is a link to the proxied message.
+func (bot *Bot) checkBannedSystem(guildID discord.GuildID, link, system, member string, userID discord.UserID) {
+	if !bot.ShouldLog() {
+		return
+	}
+
+	banned, err := bot.DB.IsSystemBanned(guildID, system)
+	if err != nil {
+		log.Errorf("checking if system %v is banned in guild %v: %v", system, guildID, err)
+		return
+	}
+
+	if !banned {
+		return
+	}
+
+	log.Debugf("system %v is banned in guild %v, sending alert", system, guildID)
+
+	accountValue := fmt.Sprintf("%v\nID: %v", userID.Mention(), userID)
+	if u, err := bot.GuildUser(guildID, userID); err == nil {
+		accountValue = fmt.Sprintf("%v\n%v\nID: %v", u.Mention(), u.Tag(), u.ID)
+	}
+
+	bot.Send(guildID, "BannedSystemEvent", SendData{
+		Embeds: []discord.Embed{{
+			Title:       "Message by banned system",
+			Description: fmt.Sprintf("A message was sent by the banned system **%v**.\n%v", system, link),
+			Color:       common.ColourRed,
+			Fields: []discord.EmbedField{
+				{Name: "System ID", Value: system, Inline: true},
+				{Name: "Member ID", Value: member, Inline: true},
+				{Name: "Linked Discord account", Value: accountValue},
+			},
+			Timestamp: discord.NowTimestamp(),
+		}},
+		Subject: EventSubject{UserID: userID},
+	})
+}
@@ -0,0 +1,139 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// messageMentions returns the users and roles mentioned in a message, excluding the author.
+func messageMentions(authorID discord.UserID, users []discord.GuildUser, roles []discord.RoleID) (userIDs []discord.UserID, roleIDs []discord.RoleID) {
+	for _, u := range users {
+		if u.ID != authorID && !common.Contains(userIDs, u.ID) {
+			userIDs = append(userIDs, u.ID)
+		}
+	}
+	for _, r := range roles {
+		if !common.Contains(roleIDs, r) {
+			roleIDs = append(roleIDs, r)
+		}
+	}
+	return userIDs, roleIDs
+}
+
+// ghostPingDelete logs a ghost ping if the deleted message mentioned anyone and was deleted within the guild's ghost ping window.
+func (bot *Bot) ghostPingDelete(m *db.Message) {
+	if m.Metadata == nil || (len(m.Metadata.Mentions) == 0 && len(m.Metadata.MentionRoles) == 0) {
+		return
+	}
+
+	window, err := bot.DB.GhostPingWindow(m.GuildID)
+	if err != nil {
+		log.Errorf("getting ghost ping window for guild %v: %v", m.GuildID, err)
+		return
+	}
+
+	if time.Since(m.ID.Time()) > window {
+		log.Debugf("message %v with mentions was deleted after the ghost ping window", m.ID)
+		return
+	}
+
+	bot.sendGhostPing(m, "deleted", m.Metadata.Mentions, m.Metadata.MentionRoles)
+}
+
+// ghostPingUpdate logs a ghost ping if an edit removed any mentions from the message.
+// The message's current mentions are always stored, so mentions added by an edit are also checked later.
+func (bot *Bot) ghostPingUpdate(ev *gateway.MessageUpdateEvent) {
+	old, err := bot.DB.GetMessage(ev.ID)
+	if err != nil {
+		log.Debugf("getting old message %v to check for ghost pings: %v", ev.ID, err)
+		return
+	}
+
+	var md db.Metadata
+	if old.Metadata != nil {
+		md = *old.Metadata
+	}
+
+	users, roles := messageMentions(ev.Author.ID, ev.Mentions, ev.MentionRoleIDs)
+
+	var removedUsers []discord.UserID
+	for _, id := range md.Mentions {
+		if !common.Contains(users, id) {
+			removedUsers = append(removedUsers, id)
+		}
+	}
+	var removedRoles []discord.RoleID
+	for _, id := range md.MentionRoles {
+		if !common.Contains(roles, id) {
+			removedRoles = append(removedRoles, id)
+		}
+	}
+
+	// store the current mentions, so that the same mentions aren't reported again on the next edit,
+	// and mentions added by this edit are reported if they're removed later
+	if len(removedUsers) > 0 || len(removedRoles) > 0 || len(users) != len(md.Mentions) || len(roles) != len(md.MentionRoles) {
+		md.Mentions, md.MentionRoles = users, roles
+		err = bot.DB.UpdateMetadata(ev.ID, md)
+		if err != nil {
+			log.Errorf("updating metadata for message %v: %v", ev.ID, err)
+		}
+	}
+
+	if len(removedUsers) == 0 && len(removedRoles) == 0 {
+		return
+	}
+
+	bot.sendGhostPing(old, "edited", removedUsers, removedRoles)
+}
+
+// sendGhostPing sends a ghost ping log. action is what happened to the message, either "deleted" or "edited".
+func (bot *Bot) sendGhostPing(m *db.Message, action string, users []discord.UserID, roles []discord.RoleID) {
+	if !bot.ShouldLog() {
+		return
+	}
+
+	var pinged []string
+	for _, id := range users {
+		pinged = append(pinged, id.Mention())
+	}
+	for _, id := range roles {
+		pinged = append(pinged, id.Mention())
+	}
+
+	authorValue := fmt.Sprintf("%v\nID: %v", m.UserID.Mention(), m.UserID)
+	if u, err := bot.GuildUser(m.GuildID, m.UserID); err == nil {
+		authorValue = fmt.Sprintf("%v\n%v\nID: %v", u.Mention(), u.Tag(), u.ID)
+	}
+
+	description := fmt.Sprintf("A message in %v that pinged someone was %v %v after being sent.",
+		m.ChannelID.Mention(), action, time.Since(m.ID.Time()).Round(time.Second))
+	if action == "edited" {
+		description = fmt.Sprintf("A message in %v was edited to remove pings. [Jump to message](%v)",
+			m.ChannelID.Mention(), messageLink(m.GuildID, m.ChannelID, m.ID))
+	}
+
+	content := common.Truncate(m.Content, 1000)
+
+	bot.Send(m.GuildID, "GhostPingEvent", SendData{
+		Embeds: []discord.Embed{{
+			Title:       "Ghost ping",
+			Description: description,
+			Color:       common.ColourOrange,
+			Fields: []discord.EmbedField{
+				{Name: "Pinged", Value: strings.Join(pinged, ", ")},
+				{Name: "Author", Value: authorValue, Inline: true},
+				{Name: "Original content", Value: content},
+			},
+			Footer:    &discord.EmbedFooter{Text: "ID: " + m.ID.String()},
+			Timestamp: discord.NowTimestamp(),
+		}},
+		Subject: EventSubject{UserID: m.UserID, ChannelID: m.ChannelID},
+	})
+}
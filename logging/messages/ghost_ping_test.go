@@ -0,0 +1,67 @@
+package messages
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestMessageMentions(t *testing.T) {
+	user := func(id discord.UserID) discord.GuildUser {
+		return discord.GuildUser{User: discord.User{ID: id}}
+	}
+
+	tests := []struct {
+		name      string
+		authorID  discord.UserID
+		users     []discord.GuildUser
+		roles     []discord.RoleID
+		wantUsers []discord.UserID
+		wantRoles []discord.RoleID
+	}{
+		{
+			name:     "no mentions",
+			authorID: 1,
+		},
+		{
+			name:      "users and roles",
+			authorID:  1,
+			users:     []discord.GuildUser{user(2), user(3)},
+			roles:     []discord.RoleID{4},
+			wantUsers: []discord.UserID{2, 3},
+			wantRoles: []discord.RoleID{4},
+		},
+		{
+			name:     "only the author",
+			authorID: 1,
+			users:    []discord.GuildUser{user(1)},
+		},
+		{
+			name:      "author is excluded",
+			authorID:  1,
+			users:     []discord.GuildUser{user(2), user(1)},
+			wantUsers: []discord.UserID{2},
+		},
+		{
+			name:      "duplicates",
+			authorID:  1,
+			users:     []discord.GuildUser{user(2), user(2)},
+			roles:     []discord.RoleID{4, 4, 5},
+			wantUsers: []discord.UserID{2},
+			wantRoles: []discord.RoleID{4, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, roles := messageMentions(tt.authorID, tt.users, tt.roles)
+			if !reflect.DeepEqual(users, tt.wantUsers) {
+				t.Errorf("users = %v, want %v", users, tt.wantUsers)
+			}
+			if !reflect.DeepEqual(roles, tt.wantRoles) {
+				t.Errorf("roles = %v, want %v", roles, tt.wantRoles)
+			}
+		})
+	}
+}
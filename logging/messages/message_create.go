@@ -29,7 +29,8 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 	// if logging for these events is disabled entirely, don't save this guild's messages in the database
 	if !channels.Channels.MessageUpdate.IsValid() &&
 		!channels.Channels.MessageDelete.IsValid() &&
-		!channels.Channels.MessageDeleteBulk.IsValid() {
+		!channels.Channels.MessageDeleteBulk.IsValid() &&
+		!channels.Channels.GhostPing.IsValid() {
 		return
 	}
 
@@ -49,21 +50,39 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 		Content: content,
 	}
 
+	// also add extra data for delete and bulk delete logging, if necessary
+	md := db.Metadata{
+		Embeds: m.Embeds,
+		Type:   m.Type,
+	}
+
+	if m.WebhookID.IsValid() {
+		md.UserID = &m.Author.ID
+		md.Username = m.Author.Username
+		md.Avatar = m.Author.Avatar
+	}
+
+	if m.Reference != nil && m.Reference.MessageID.IsValid() {
+		md.ReferencedMessageID = &m.Reference.MessageID
+	}
+
+	for _, sticker := range m.Stickers {
+		md.Stickers = append(md.Stickers, sticker.Name)
+	}
+
 	for _, attachment := range m.Attachments {
 		msg.AttachmentSize += attachment.Size
+
+		md.Attachments = append(md.Attachments, db.Attachment{
+			Filename: attachment.Filename,
+			URL:      attachment.URL,
+		})
 	}
 
-	// also add extra data for bulk delete logging, if necessary
-	if len(m.Embeds) > 0 || m.WebhookID.IsValid() {
-		msg.Metadata = &db.Metadata{}
+	md.Mentions, md.MentionRoles = messageMentions(m.Author.ID, m.Mentions, m.MentionRoleIDs)
 
-		if m.WebhookID.IsValid() {
-			msg.Metadata.UserID = &m.Author.ID
-			msg.Metadata.Username = m.Author.Username
-			msg.Metadata.Avatar = m.Author.Avatar
-		} else {
-			msg.Metadata.Embeds = m.Embeds
-		}
+	if !md.IsEmpty() {
+		msg.Metadata = &md
 	}
 
 	err = bot.DB.InsertMessage(msg)
@@ -72,17 +91,8 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 		return
 	}
 
-	// check if message was from a PluralKit webhook
-	var isPK bool
-	for _, id := range pkBots {
-		if m.ApplicationID == discord.AppID(id) {
-			isPK = true
-			break
-		}
-	}
-
-	// if not a proxied message, we can stop here
-	if !isPK {
+	// if not a message proxied by a PluralKit-compatible bot, we can stop here
+	if _, isPK := bot.PluralKitBot(discord.UserID(m.ApplicationID)); !isPK {
 		return
 	}
 
@@ -94,24 +104,11 @@ func (bot *Bot) messageCreate(m *gateway.MessageCreateEvent) {
 	}
 }
 
-var (
-	pkLinkRegex   = regexp.MustCompile(`^https:\/\/discord.com\/channels\/\d+\/(\d+)\/\d+$`)
-	pkFooterRegex = regexp.MustCompile(`^System ID: (\w{5,6}) \| Member ID: (\w{5,6}) \| Sender: .+ \((\d+)\) \| Message ID: (\d+) \| Original Message ID: (\d+)$`)
-)
-
-var pkBots = [...]discord.UserID{
-	466378653216014359,
-}
+var pkLinkRegex = regexp.MustCompile(`^https:\/\/discord.com\/channels\/\d+\/(\d+)\/\d+$`)
 
 func (bot *Bot) pkMessageCreate(m *gateway.MessageCreateEvent) {
 	// we only want to check PluralKit-compatible bots
-	var isPK bool
-	for _, id := range pkBots {
-		if m.Author.ID == id {
-			isPK = true
-			break
-		}
-	}
+	pk, isPK := bot.PluralKitBot(m.Author.ID)
 	if !isPK {
 		return
 	}
@@ -127,41 +124,29 @@ func (bot *Bot) pkMessageCreate(m *gateway.MessageCreateEvent) {
 	}
 
 	// find matches in embed footer
-	groups := pkFooterRegex.FindStringSubmatch(m.Embeds[0].Footer.Text)
-	if len(groups) < 6 {
+	pkLog, ok := pk.ParseLog(m.Embeds[0].Footer.Text)
+	if !ok {
 		return
 	}
 
-	var (
-		sysID             = groups[1]
-		memberID          = groups[2]
-		userID            discord.UserID
-		msgID             discord.MessageID
-		originalMessageID discord.Snowflake
-	)
-
-	// all snowflakes will be valid
-	{
-		sf, _ := discord.ParseSnowflake(groups[3])
-		userID = discord.UserID(sf)
-		sf, _ = discord.ParseSnowflake(groups[4])
-		msgID = discord.MessageID(sf)
+	msgID, originalMessageID := pkLog.MessageID, pkLog.OriginalMessageID
 
-		// add the original message ID to the list of proxy trigger messages
-		// so that we don't log it being deleted
-		originalMessageID, _ = discord.ParseSnowflake(groups[5])
-		bot.proxyTriggers.Add(discord.MessageID(originalMessageID))
-	}
+	// add the original message ID to the list of proxy trigger messages
+	// so that we don't log it being deleted
+	bot.proxyTriggers.Add(originalMessageID)
 
-	err := bot.DB.UpdatePKInfo(msgID, discord.MessageID(originalMessageID), pkgo.Snowflake(userID), sysID, memberID)
+	err := bot.DB.UpdatePKInfo(msgID, originalMessageID, pkgo.Snowflake(pkLog.Sender), pkLog.System, pkLog.Member)
 	if err != nil {
 		log.Errorf("updating pk info for message %v: %v", msgID, err)
 	}
 
 	log.Debugf("saved pk info for message %v, original %v", msgID, originalMessageID)
 
+	// alert the guild if the system is banned
+	bot.checkBannedSystem(m.GuildID, m.Content, pkLog.System, pkLog.Member, pkLog.Sender)
+
 	// delete the original message from the DB
-	err = bot.DB.DeleteMessage(discord.MessageID(originalMessageID))
+	err = bot.DB.DeleteMessage(originalMessageID)
 	if err != nil {
 		log.Errorf("deleting original message %v from db: %v", originalMessageID, err)
 	}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"emperror.dev/errors"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/jackc/pgx/v5"
 	"github.com/starshine-sys/catalogger/v2/common"
 	"github.com/starshine-sys/catalogger/v2/common/log"
 	"github.com/starshine-sys/pkgo/v2"
@@ -35,7 +37,7 @@ func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
 	if !bot.DB.HasPKInfo(ev.ID) && ev.ID.Time().Before(time.Now().Add(1*time.Minute)) {
 		log.Debugf("fetching PK info for message %v", ev.ID)
 
-		pkm, err := bot.PK.Message(pkgo.Snowflake(ev.ID))
+		pkm, err := bot.pkMessage(ev.ID)
 		if err == nil {
 			if pkm.ID != pkgo.Snowflake(ev.ID) {
 				log.Debugf("message with ID %v is a proxy trigger message, saving PK info and ignoring delete", ev.ID)
@@ -56,6 +58,11 @@ func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
 				if err != nil {
 					log.Errorf("deleting original proxy trigger message %v: %v", ev.ID, err)
 				}
+
+				// alert the guild if the system is banned
+				bot.checkBannedSystem(ev.GuildID,
+					messageLink(ev.GuildID, discord.ChannelID(pkm.Channel), discord.MessageID(pkm.ID)),
+					pkm.System.ID, pkm.Member.ID, discord.UserID(pkm.Sender))
 				return
 			}
 		} else {
@@ -74,8 +81,9 @@ func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
 		return
 	}
 
-	if !lc.Channels.MessageDelete.IsValid() {
-		log.Debugf("message delete logs are disabled in guild %v", ev.GuildID)
+	// deleted messages are mirrored to the author's dossier even if they aren't logged
+	if !lc.Channels.MessageDelete.IsValid() && !lc.Channels.GhostPing.IsValid() && !bot.HasDossiers(ev.GuildID) {
+		log.Debugf("message delete and ghost ping logs and dossiers are disabled in guild %v", ev.GuildID)
 		return
 	}
 
@@ -92,14 +100,27 @@ func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
 
 	m, err := bot.DB.GetMessage(ev.ID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Debugf("message %v is not stored, logging it as an unknown message if enabled", ev.ID)
+			bot.unknownMessageDelete(ev, lc)
+			return
+		}
+
 		log.Errorf("getting message object for %v from db: %v", ev.ID, err)
 		return
 	}
 
-	// check if the channel is ignored
-	if common.Contains(lc.Ignores.GlobalChannels, m.ChannelID) {
-		log.Debugf("message in channel %v is ignored", m.ChannelID)
-		return
+	if lc.Channels.GhostPing.IsValid() {
+		bot.ghostPingDelete(m)
+	}
+
+	// check ignores and redirects
+	route := bot.RouteWith(lc, m.GuildID, "MessageDeleteEvent", EventSubject{ChannelID: m.ChannelID, UserID: m.UserID})
+	if route.Suppressed() {
+		log.Debugf("message %v is not logged: %v", m.ID, route.Reason)
+		if !bot.HasDossiers(m.GuildID) {
+			return
+		}
 	}
 
 	rootChannel, err := bot.Cabinet.RootChannel(context.Background(), m.ChannelID)
@@ -108,26 +129,6 @@ func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
 		return
 	}
 
-	if common.Contains(lc.Ignores.GlobalChannels, rootChannel.ID) ||
-		(rootChannel.ParentID.IsValid() && common.Contains(lc.Ignores.GlobalChannels, rootChannel.ParentID)) {
-		log.Debugf("message in channel %v is ignored because root or category is", m.ChannelID)
-		return
-	}
-
-	// check if user is ignored globally
-	if common.Contains(lc.Ignores.GlobalUsers, m.UserID) {
-		log.Debugf("message %v is ignored because user %v is ignored globally", m.ID, m.UserID)
-		return
-	}
-
-	// check if user is ignored *in this channel*
-	if common.Contains(lc.Ignores.PerChannel[m.ChannelID.String()], m.UserID) ||
-		common.Contains(lc.Ignores.PerChannel[rootChannel.ID.String()], m.UserID) ||
-		(rootChannel.ParentID.IsValid() && common.Contains(lc.Ignores.PerChannel[rootChannel.ParentID.String()], m.UserID)) {
-		log.Debugf("message %v is ignored because user %v is ignored in the channel", m.ID, m.UserID)
-		return
-	}
-
 	// everything checks out, start building embed!
 	embed := discord.Embed{
 		Title:       fmt.Sprintf("Message by %v deleted", m.Username),
@@ -187,31 +188,65 @@ func (bot *Bot) messageDelete(ev *gateway.MessageDeleteEvent) {
 	// add PluralKit information
 	// these fields will always *both* be null or *both* be non-null
 	if m.System != nil && m.Member != nil {
-		embed.Fields = append(embed.Fields, []discord.EmbedField{
-			{Name: "\u200b", Value: "**PluralKit information**"},
-			{Name: "System ID", Value: *m.System, Inline: true},
-			{Name: "Member ID", Value: *m.Member, Inline: true},
-		}...)
+		fields, colour := bot.pkFields(*m.System, *m.Member)
+		embed.Fields = append(embed.Fields, fields...)
+		if colour != 0 {
+			embed.Color = colour
+		}
+	}
+
+	// add replies, attachments, stickers, and embeds
+	embed.Fields = append(embed.Fields, metadataFields(m)...)
+
+	// add edit history, if the message was edited
+	// the first revision is the original content, so it's not counted as an edit
+	revisions, err := bot.DB.RevisionCount(m.ID)
+	if err != nil {
+		log.Errorf("getting revision count for message %v: %v", m.ID, err)
+	} else if revisions > 1 {
+		edits := "once"
+		if revisions > 2 {
+			edits = fmt.Sprintf("%v times", revisions-1)
+		}
+
+		embed.Fields = append(embed.Fields, discord.EmbedField{
+			Name:  "Edits",
+			Value: fmt.Sprintf("This message was edited %v.", edits),
+		})
 	}
 
-	// get the correct log channel (taking into account redirects)
-	logChannel := lc.Channels.MessageDelete
-	if id, ok := lc.Redirects[m.ChannelID.String()]; ok { // check this channel's ID
-		logChannel = id
-	} else if id, ok := lc.Redirects[rootChannel.ID.String()]; ok { // check root channel's ID (parent of thread)
-		logChannel = id
-	} else if id, ok := lc.Redirects[rootChannel.ParentID.String()]; ok && rootChannel.ParentID.IsValid() { // check root channel's parent ID (category, if in category)
-		logChannel = id
+	// highlight the author if they're on the watchlist
+	if field, ok := bot.WatchlistField(m.GuildID, m.UserID); ok {
+		embed.Fields = append(embed.Fields, field)
 	}
 
-	if !logChannel.IsValid() {
-		log.Warnf("delete log for message %v in channel %v/guild %v got to end of handler, but there is no valid log channel", m.ID, m.ChannelID, m.GuildID)
+	// Send also mirrors the log to the author's dossier, so if it isn't logged, only the dossier gets it
+	if route.Suppressed() {
+		bot.SendDossier(m.GuildID, m.UserID, []discord.Embed{embed})
 		return
 	}
 
 	// send log message!
 	bot.Send(m.GuildID, ev, SendData{
-		ChannelID: logChannel,
-		Embeds:    []discord.Embed{embed},
+		ChannelIDs:      route.ChannelIDs,
+		LinkedChannelID: route.LinkedChannelID,
+		Embeds:          []discord.Embed{embed},
+		Subject:         EventSubject{UserID: m.UserID},
 	})
 }
+
+// pkMessage gets a message from every PluralKit-compatible bot's API, returning the first one found.
+// If no API has the message, an error is returned. Errors other than the message not being found take priority.
+func (bot *Bot) pkMessage(id discord.MessageID) (m pkgo.Message, err error) {
+	for _, pk := range bot.PluralKitBots {
+		pkm, pkErr := pk.API.Message(pkgo.Snowflake(id))
+		if pkErr == nil {
+			return pkm, nil
+		}
+
+		if pkerr, ok := pkErr.(*pkgo.PKAPIError); err == nil || !ok || pkerr.Code != pkgo.MessageNotFound {
+			err = pkErr
+		}
+	}
+	return m, err
+}
@@ -0,0 +1,121 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func (bot *Bot) messageDeleteBulk(ev *gateway.MessageDeleteBulkEvent) {
+	if !ev.GuildID.IsValid() {
+		return
+	}
+
+	lc, err := bot.DB.Channels(ev.GuildID)
+	if err != nil {
+		log.Errorf("getting channels for guild %v: %v", ev.GuildID, err)
+		return
+	}
+
+	if !lc.Channels.MessageDeleteBulk.IsValid() {
+		log.Debugf("bulk message delete logs are disabled in guild %v", ev.GuildID)
+		return
+	}
+
+	defer func() {
+		for _, id := range ev.IDs {
+			err = bot.DB.DeleteMessage(id)
+			if err != nil {
+				log.Errorf("deleting message %v from db: %v", id, err)
+			}
+		}
+	}()
+
+	if !bot.ShouldLog() {
+		return
+	}
+
+	// check ignores and redirects
+	// ignored users are checked for each message below
+	route := bot.RouteWith(lc, ev.GuildID, "MessageDeleteBulkEvent", EventSubject{ChannelID: ev.ChannelID})
+	if route.Suppressed() {
+		log.Debugf("bulk delete in %v is not logged: %v", ev.ChannelID, route.Reason)
+		return
+	}
+
+	ids := append([]discord.MessageID(nil), ev.IDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var (
+		msgs   []*db.Message
+		stored int
+	)
+	for _, id := range ids {
+		m, err := bot.DB.GetMessage(id)
+		if err != nil {
+			log.Debugf("getting message %v from db: %v", id, err)
+			continue
+		}
+		stored++
+
+		// ignored users are left out of the log entirely
+		if bot.UserIgnored(lc, ev.GuildID, route.Channels, m.UserID) {
+			continue
+		}
+
+		msgs = append(msgs, m)
+	}
+
+	var b strings.Builder
+	for _, m := range msgs {
+		fmt.Fprintf(&b, "[%v] %v (%v)", m.ID.Time().UTC().Format("2006-01-02 15:04:05"), m.Username, m.UserID)
+		if m.System != nil && m.Member != nil {
+			fmt.Fprintf(&b, " (PluralKit system %v, member %v)", *m.System, *m.Member)
+		}
+		fmt.Fprintf(&b, "\n%v\n", m.Content)
+
+		for _, line := range metadataLines(m) {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	ch, err := bot.Cabinet.Channel(context.Background(), ev.ChannelID)
+	if err != nil {
+		ch = discord.Channel{
+			ID:   ev.ChannelID,
+			Name: "unknown",
+		}
+	}
+
+	embed := discord.Embed{
+		Title: "Messages bulk deleted",
+		Description: fmt.Sprintf("%v messages were deleted in %v (%v).\n%v of these messages were stored, %v are included in the attached file.",
+			len(ev.IDs), ch.Mention(), ch.Name, stored, len(msgs)),
+		Color:     common.ColourRed,
+		Timestamp: discord.NowTimestamp(),
+	}
+
+	var files []sendpart.File
+	if len(msgs) > 0 {
+		files = []sendpart.File{{
+			Name:   fmt.Sprintf("bulk-delete-%v.txt", ev.ChannelID),
+			Reader: strings.NewReader(b.String()),
+		}}
+	}
+
+	bot.Send(ev.GuildID, ev, SendData{
+		ChannelIDs:      route.ChannelIDs,
+		LinkedChannelID: route.LinkedChannelID,
+		Embeds:          []discord.Embed{embed},
+		Files:           files,
+	})
+}
@@ -0,0 +1,86 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/common/duration"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// unknownMessageDelete logs the deletion of a message that isn't stored in the database,
+// for example because it was sent before Catalogger joined the server.
+// This is only done if the guild has opted in to it.
+// As we don't know the message's author, only channel ignores are checked.
+func (bot *Bot) unknownMessageDelete(ev *gateway.MessageDeleteEvent, lc db.Channels) {
+	enabled, err := bot.DB.LogUnknownMessages(ev.GuildID)
+	if err != nil {
+		log.Errorf("checking if guild %v logs unknown messages: %v", ev.GuildID, err)
+		return
+	}
+
+	if !enabled {
+		log.Debugf("guild %v does not log unknown messages", ev.GuildID)
+		return
+	}
+
+	// check ignores and redirects
+	route := bot.RouteWith(lc, ev.GuildID, "MessageDeleteEvent", EventSubject{ChannelID: ev.ChannelID})
+	if route.Suppressed() {
+		log.Debugf("unknown message %v is not logged: %v", ev.ID, route.Reason)
+		return
+	}
+
+	rootChannel, err := bot.Cabinet.RootChannel(context.Background(), ev.ChannelID)
+	if err != nil {
+		log.Errorf("getting root channel for channel %v: %v", ev.ChannelID, err)
+		return
+	}
+
+	embed := discord.Embed{
+		Title: "Unknown message deleted",
+		Description: "A message that Catalogger did not store was deleted.\n" +
+			"It may have been sent before Catalogger joined the server, or be older than Catalogger's message storage.",
+		Color: common.ColourRed,
+		Footer: &discord.EmbedFooter{
+			Text: "ID: " + ev.ID.String(),
+		},
+		Timestamp: discord.Timestamp(ev.ID.Time()),
+	}
+
+	// make channel field
+	ch, err := bot.Cabinet.Channel(context.Background(), ev.ChannelID)
+	if err != nil {
+		ch = discord.Channel{
+			ID:   ev.ChannelID,
+			Name: "unknown",
+		}
+	}
+
+	channelField := discord.EmbedField{
+		Name:   "Channel",
+		Value:  fmt.Sprintf("%v\nID: %v", ch.Mention(), ch.ID),
+		Inline: true,
+	}
+
+	if common.IsThread(ch) {
+		channelField.Value = fmt.Sprintf("%v\nID: %v\n\n**Thread**\n%v\nID: %v", rootChannel.Mention(), rootChannel.ID, ch.Name, ch.ID)
+	}
+
+	// the only other thing we know is when the message was sent, from its ID
+	embed.Fields = append(embed.Fields, channelField, discord.EmbedField{
+		Name:   "Sent",
+		Value:  fmt.Sprintf("<t:%v>\n%v", ev.ID.Time().Unix(), duration.FormatTime(ev.ID.Time())),
+		Inline: true,
+	})
+
+	bot.Send(ev.GuildID, ev, SendData{
+		ChannelIDs:      route.ChannelIDs,
+		LinkedChannelID: route.LinkedChannelID,
+		Embeds:          []discord.Embed{embed},
+	})
+}
@@ -40,44 +40,31 @@ func (bot *Bot) messageUpdate(ev *gateway.MessageUpdateEvent) {
 		return
 	}
 
-	if !lc.Channels.MessageUpdate.IsValid() {
-		log.Debugf("message update logs are disabled in guild %v", ev.GuildID)
-		return
-	}
-
-	// check if the channel is ignored
-	if common.Contains(lc.Ignores.GlobalChannels, ev.ChannelID) {
-		log.Debugf("message in channel %v is ignored", ev.ChannelID)
-		return
-	}
-
-	rootChannel, err := bot.Cabinet.RootChannel(context.Background(), ev.ChannelID)
-	if err != nil {
-		log.Errorf("getting root channel for channel %v: %v", ev.ChannelID, err)
-		return
+	if lc.Channels.GhostPing.IsValid() {
+		bot.ghostPingUpdate(ev)
 	}
 
-	if common.Contains(lc.Ignores.GlobalChannels, rootChannel.ID) ||
-		(rootChannel.ParentID.IsValid() && common.Contains(lc.Ignores.GlobalChannels, rootChannel.ParentID)) {
-		log.Debugf("message in channel %v is ignored because root or category is", ev.ChannelID)
+	if !lc.Channels.MessageUpdate.IsValid() {
+		log.Debugf("message update logs are disabled in guild %v", ev.GuildID)
 		return
 	}
 
-	// check if user is ignored globally
-	if common.Contains(lc.Ignores.GlobalUsers, ev.Author.ID) {
-		log.Debugf("message %v is ignored because user %v is ignored globally", ev.ID, ev.Author.ID)
+	// check ignores and redirects
+	route := bot.RouteWith(lc, ev.GuildID, "MessageUpdateEvent", EventSubject{ChannelID: ev.ChannelID, UserID: ev.Author.ID})
+	if route.Suppressed() {
+		log.Debugf("message %v is not logged: %v", ev.ID, route.Reason)
 		return
 	}
 
-	// check if user is ignored *in this channel*
-	if common.Contains(lc.Ignores.PerChannel[ev.ChannelID.String()], ev.Author.ID) ||
-		common.Contains(lc.Ignores.PerChannel[rootChannel.ID.String()], ev.Author.ID) ||
-		(rootChannel.ParentID.IsValid() && common.Contains(lc.Ignores.PerChannel[rootChannel.ParentID.String()], ev.Author.ID)) {
-		log.Debugf("message %v is ignored because user %v is ignored in the channel", ev.ID, ev.Author.ID)
-		return
-	}
+	// set to false if the content is identical to the stored message,
+	// so that we don't store a duplicate revision
+	contentChanged := true
 
 	defer func() {
+		if !contentChanged {
+			return
+		}
+
 		content := ev.Content
 		if ev.Content == "" {
 			content = "None"
@@ -99,18 +86,20 @@ func (bot *Bot) messageUpdate(ev *gateway.MessageUpdateEvent) {
 		}
 	}()
 
-	if !bot.ShouldLog() {
+	// this is checked before test mode, so that it doesn't store duplicate revisions either
+	old, err := bot.DB.GetMessage(ev.ID)
+	if err == nil && old.Content == ev.Content {
+		log.Debugf("new content for message %v was identical to old content", ev.ID)
+		contentChanged = false
 		return
 	}
 
-	old, err := bot.DB.GetMessage(ev.ID)
-	if err != nil {
-		log.Errorf("getting old message %v: %v", ev.ID, err)
+	if !bot.ShouldLog() {
 		return
 	}
 
-	if old.Content == ev.Content {
-		log.Debugf("new content for message %v was identical to old content", ev.ID)
+	if err != nil {
+		log.Errorf("getting old message %v: %v", ev.ID, err)
 		return
 	}
 
@@ -255,22 +244,11 @@ func (bot *Bot) messageUpdate(ev *gateway.MessageUpdateEvent) {
 			}
 		}
 
-		embed.Fields = append(embed.Fields, []discord.EmbedField{
-			{
-				Name:  "\u200b",
-				Value: "**PluralKit information**",
-			},
-			{
-				Name:   "System ID",
-				Value:  *old.System,
-				Inline: true,
-			},
-			{
-				Name:   "Member ID",
-				Value:  *old.Member,
-				Inline: true,
-			},
-		}...)
+		fields, colour := bot.pkFields(*old.System, *old.Member)
+		embed.Fields = append(embed.Fields, fields...)
+		if colour != 0 {
+			embed.Color = colour
+		}
 	}
 
 	// add link to message
@@ -279,21 +257,6 @@ func (bot *Bot) messageUpdate(ev *gateway.MessageUpdateEvent) {
 		Value: fmt.Sprintf("https://discord.com/channels/%v/%v/%v", ev.GuildID, ev.ChannelID, ev.ID),
 	})
 
-	// get the correct log channel (taking into account redirects)
-	logChannel := lc.Channels.MessageUpdate
-	if id, ok := lc.Redirects[ev.ChannelID.String()]; ok { // check this channel's ID
-		logChannel = id
-	} else if id, ok := lc.Redirects[rootChannel.ID.String()]; ok { // check root channel's ID (parent of thread)
-		logChannel = id
-	} else if id, ok := lc.Redirects[rootChannel.ParentID.String()]; ok && rootChannel.ParentID.IsValid() { // check root channel's parent ID (category, if in category)
-		logChannel = id
-	}
-
-	if !logChannel.IsValid() {
-		log.Warnf("update log for message %v in channel %v/guild %v got to end of handler, but there is no valid log channel", ev.ID, ev.ChannelID, ev.GuildID)
-		return
-	}
-
 	var files []sendpart.File
 	if len(ev.Content) >= 2000 || len(old.Content) >= 2000 {
 		files = []sendpart.File{
@@ -310,8 +273,9 @@ func (bot *Bot) messageUpdate(ev *gateway.MessageUpdateEvent) {
 
 	// send log
 	bot.Send(ev.GuildID, ev, SendData{
-		ChannelID: logChannel,
-		Embeds:    []discord.Embed{embed},
-		Files:     files,
+		ChannelIDs:      route.ChannelIDs,
+		LinkedChannelID: route.LinkedChannelID,
+		Embeds:          []discord.Embed{embed},
+		Files:           files,
 	})
 }
@@ -0,0 +1,130 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/common"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+// messageTypes are names for message types that are shown in logs.
+// Normal messages and replies aren't included, as replies are shown separately.
+var messageTypes = map[discord.MessageType]string{
+	discord.ChannelPinnedMessage:        "Pin notification",
+	discord.GuildMemberJoinMessage:      "Member join notification",
+	discord.NitroBoostMessage:           "Server boost notification",
+	discord.NitroTier1Message:           "Server boost notification",
+	discord.NitroTier2Message:           "Server boost notification",
+	discord.NitroTier3Message:           "Server boost notification",
+	discord.ChannelFollowAddMessage:     "Channel follow notification",
+	discord.ThreadCreatedMessage:        "Thread created notification",
+	discord.ChatInputCommandMessage:     "Slash command",
+	discord.ThreadStarterMessage:        "Thread starter message",
+	discord.ContextMenuCommand:          "Context menu command",
+	discord.AutoModerationActionMessage: "AutoMod action",
+}
+
+// messageLink returns a link to the given message.
+func messageLink(guildID discord.GuildID, channelID discord.ChannelID, id discord.MessageID) string {
+	return fmt.Sprintf("https://discord.com/channels/%v/%v/%v", guildID, channelID, id)
+}
+
+// metadataFields returns embed fields for the message's metadata, if it has any.
+func metadataFields(m *db.Message) (fields []discord.EmbedField) {
+	if m.Metadata == nil {
+		return nil
+	}
+	md := m.Metadata
+
+	if name, ok := messageTypes[md.Type]; ok {
+		fields = append(fields, discord.EmbedField{
+			Name:   "Type",
+			Value:  name,
+			Inline: true,
+		})
+	}
+
+	if md.ReferencedMessageID != nil {
+		fields = append(fields, discord.EmbedField{
+			Name:   "Reply to",
+			Value:  messageLink(m.GuildID, m.ChannelID, *md.ReferencedMessageID),
+			Inline: true,
+		})
+	}
+
+	if len(md.Attachments) > 0 {
+		names := make([]string, 0, len(md.Attachments))
+		for _, a := range md.Attachments {
+			names = append(names, a.Filename)
+		}
+
+		fields = append(fields, discord.EmbedField{
+			Name:  "Attachments",
+			Value: common.Truncate(strings.Join(names, ", "), 1000),
+		})
+	}
+
+	if len(md.Stickers) > 0 {
+		fields = append(fields, discord.EmbedField{
+			Name:  "Stickers",
+			Value: common.Truncate(strings.Join(md.Stickers, ", "), 1000),
+		})
+	}
+
+	if len(md.Embeds) > 0 {
+		fields = append(fields, discord.EmbedField{
+			Name:   "Embeds",
+			Value:  fmt.Sprint(len(md.Embeds)),
+			Inline: true,
+		})
+	}
+
+	return fields
+}
+
+// metadataLines returns the message's metadata as lines of text, used in bulk delete logs.
+func metadataLines(m *db.Message) (lines []string) {
+	if m.Metadata == nil {
+		return nil
+	}
+	md := m.Metadata
+
+	if name, ok := messageTypes[md.Type]; ok {
+		lines = append(lines, "Type: "+name)
+	}
+
+	if md.ReferencedMessageID != nil {
+		lines = append(lines, "Reply to "+messageLink(m.GuildID, m.ChannelID, *md.ReferencedMessageID))
+	}
+
+	if len(md.Attachments) > 0 {
+		names := make([]string, 0, len(md.Attachments))
+		for _, a := range md.Attachments {
+			names = append(names, a.Filename)
+		}
+		lines = append(lines, "Attachments: "+strings.Join(names, ", "))
+
+		for _, a := range md.Attachments {
+			lines = append(lines, "  "+a.URL)
+		}
+	}
+
+	if len(md.Stickers) > 0 {
+		lines = append(lines, "Stickers: "+strings.Join(md.Stickers, ", "))
+	}
+
+	for _, e := range md.Embeds {
+		s := "Embed:"
+		if e.Title != "" {
+			s += " " + e.Title
+		}
+		if e.Description != "" {
+			s += " - " + strings.ReplaceAll(common.Truncate(e.Description, 200), "\n", " ")
+		}
+		lines = append(lines, s)
+	}
+
+	return lines
+}
@@ -0,0 +1,78 @@
+package messages
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/starshine-sys/catalogger/v2/db"
+)
+
+func TestMetadataLines(t *testing.T) {
+	replyID := discord.MessageID(3)
+
+	tests := []struct {
+		name string
+		md   *db.Metadata
+		want []string
+	}{
+		{
+			name: "no metadata",
+		},
+		{
+			name: "normal message",
+			md:   &db.Metadata{Username: "proxied"},
+		},
+		{
+			name: "pin notification",
+			md:   &db.Metadata{Type: discord.ChannelPinnedMessage},
+			want: []string{"Type: Pin notification"},
+		},
+		{
+			name: "reply",
+			md:   &db.Metadata{ReferencedMessageID: &replyID},
+			want: []string{"Reply to https://discord.com/channels/1/2/3"},
+		},
+		{
+			name: "attachments",
+			md: &db.Metadata{Attachments: []db.Attachment{
+				{Filename: "a.png", URL: "https://cdn.example/a.png"},
+				{Filename: "b.txt", URL: "https://cdn.example/b.txt"},
+			}},
+			want: []string{
+				"Attachments: a.png, b.txt",
+				"  https://cdn.example/a.png",
+				"  https://cdn.example/b.txt",
+			},
+		},
+		{
+			name: "stickers",
+			md:   &db.Metadata{Stickers: []string{"wave", "cat"}},
+			want: []string{"Stickers: wave, cat"},
+		},
+		{
+			name: "embeds",
+			md: &db.Metadata{Embeds: []discord.Embed{
+				{Title: "Title"},
+				{Title: "Title", Description: "first\nsecond"},
+				{Description: strings.Repeat("a", 250)},
+			}},
+			want: []string{
+				"Embed: Title",
+				"Embed: Title - first second",
+				"Embed: - " + strings.Repeat("a", 200) + "…",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &db.Message{GuildID: 1, ChannelID: 2, Metadata: tt.md}
+
+			got := metadataLines(m)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("metadataLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
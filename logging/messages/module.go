@@ -5,9 +5,11 @@ import (
 	"github.com/starshine-sys/catalogger/v2/bot"
 	"github.com/starshine-sys/catalogger/v2/common"
 	"github.com/starshine-sys/catalogger/v2/common/log"
+	"golang.org/x/time/rate"
 )
 
 type SendData = bot.SendData
+type EventSubject = bot.EventSubject
 
 type Bot struct {
 	*bot.Bot
@@ -16,6 +18,9 @@ type Bot struct {
 	proxyTriggers *common.Set[discord.MessageID]
 	// pluralkit messages that already have data from the pk;log channel
 	handledMessages *common.Set[discord.MessageID]
+
+	// rate limiter for looking up PluralKit system and member information
+	pkLimiter *rate.Limiter
 }
 
 func Setup(root *bot.Bot) {
@@ -26,6 +31,7 @@ func Setup(root *bot.Bot) {
 
 		proxyTriggers:   common.NewSet[discord.MessageID](),
 		handledMessages: common.NewSet[discord.MessageID](),
+		pkLimiter:       rate.NewLimiter(2, 5),
 	}
 
 	ignoreApplications[0] = discord.AppID(bot.Me().ID)
@@ -37,7 +43,11 @@ func Setup(root *bot.Bot) {
 		bot.pkMessageCreate,
 		// message delete handler
 		bot.messageDelete,
+		// bulk message delete handler
+		bot.messageDeleteBulk,
 		// message update handler
 		bot.messageUpdate,
 	)
+
+	go bot.revisionCleanupLoop()
 }
@@ -0,0 +1,140 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/starshine-sys/catalogger/v2/common/log"
+	"github.com/starshine-sys/pkgo/v2"
+)
+
+// pkInfoTTL is how long PluralKit system and member information is cached for.
+const pkInfoTTL = time.Hour
+
+// pkInfo is cached information about a PluralKit system or member.
+// If Name is empty, the system or member wasn't found or its name is private.
+type pkInfo struct {
+	Name     string `json:"name"`
+	Pronouns string `json:"pronouns,omitempty"`
+	Color    string `json:"color,omitempty"`
+}
+
+func pkSystemKey(id string) string { return "pk-system:" + id }
+func pkMemberKey(id string) string { return "pk-member:" + id }
+
+// pkSystem returns information about the given PluralKit system, from the cache if possible.
+func (bot *Bot) pkSystem(id string) (info pkInfo, ok bool) {
+	return bot.pkCached(pkSystemKey(id), func(s *pkgo.Session) (pkInfo, error) {
+		sys, err := s.System(id)
+		if err != nil {
+			return pkInfo{}, err
+		}
+		return pkInfo{Name: sys.Name, Color: sys.Color}, nil
+	})
+}
+
+// pkMember returns information about the given PluralKit member, from the cache if possible.
+func (bot *Bot) pkMember(id string) (info pkInfo, ok bool) {
+	return bot.pkCached(pkMemberKey(id), func(s *pkgo.Session) (pkInfo, error) {
+		m, err := s.Member(id)
+		if err != nil {
+			return pkInfo{}, err
+		}
+
+		name := m.Name
+		if m.DisplayName != "" {
+			name = m.DisplayName
+		}
+		return pkInfo{Name: name, Pronouns: m.Pronouns, Color: string(m.Color)}, nil
+	})
+}
+
+// pkCached gets a cached value from Redis, or fetches it from the first PluralKit-compatible API that has it.
+// API requests are rate limited; if the limit is hit, ok is false and nothing is cached.
+func (bot *Bot) pkCached(key string, fetch func(*pkgo.Session) (pkInfo, error)) (info pkInfo, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var raw []byte
+	err := bot.DB.Redis.Do(ctx, radix.Cmd(&raw, "GET", key))
+	if err != nil {
+		log.Errorf("getting cached PluralKit info %v: %v", key, err)
+	} else if raw != nil {
+		err = json.Unmarshal(raw, &info)
+		if err == nil {
+			return info, info.Name != ""
+		}
+		log.Errorf("unmarshaling cached PluralKit info %v: %v", key, err)
+	}
+
+	if !bot.pkLimiter.Allow() {
+		log.Debugf("PluralKit info lookups are rate limited, not fetching %v", key)
+		return info, false
+	}
+
+	for _, pk := range bot.PluralKitBots {
+		info, err = fetch(pk.API)
+		if err == nil {
+			break
+		}
+
+		if pkerr, ok := err.(*pkgo.PKAPIError); !ok ||
+			(pkerr.Code != pkgo.SystemNotFound && pkerr.Code != pkgo.MemberNotFound) {
+			// don't cache anything if the API errored, so we try again next time
+			log.Errorf("fetching PluralKit info %v: %v", key, err)
+			return info, false
+		}
+	}
+
+	// if no API has the system or member, this caches an empty name so we don't keep looking it up
+	b, err := json.Marshal(info)
+	if err != nil {
+		log.Errorf("marshaling PluralKit info %v: %v", key, err)
+		return info, info.Name != ""
+	}
+
+	err = bot.DB.Redis.Do(ctx, radix.Cmd(nil, "SET", key, string(b), "EX", strconv.Itoa(int(pkInfoTTL.Seconds()))))
+	if err != nil {
+		log.Errorf("caching PluralKit info %v: %v", key, err)
+	}
+
+	return info, info.Name != ""
+}
+
+// pkFields returns the PluralKit information fields for a message's system and member,
+// and the member's colour (or 0 if the member has no colour or wasn't found).
+func (bot *Bot) pkFields(system, member string) (fields []discord.EmbedField, colour discord.Color) {
+	sys, sysOk := bot.pkSystem(system)
+
+	systemValue := system
+	if sysOk {
+		systemValue = fmt.Sprintf("%v (%v)", sys.Name, system)
+	}
+
+	memberValue := member
+	if m, ok := bot.pkMember(member); ok {
+		memberValue = fmt.Sprintf("%v (%v)", m.Name, member)
+		if sysOk {
+			memberValue += " of " + sys.Name
+		}
+		if m.Pronouns != "" {
+			memberValue += "\nPronouns: " + m.Pronouns
+		}
+
+		if c, err := strconv.ParseUint(strings.TrimPrefix(m.Color, "#"), 16, 32); err == nil && len(m.Color) >= 6 {
+			colour = discord.Color(c)
+		}
+	}
+
+	return []discord.EmbedField{
+		{Name: "\u200b", Value: "**PluralKit information**"},
+		{Name: "Member", Value: memberValue, Inline: true},
+		{Name: "System", Value: systemValue, Inline: true},
+	}, colour
+}
@@ -0,0 +1,27 @@
+package messages
+
+import (
+	"time"
+
+	"github.com/starshine-sys/catalogger/v2/common/log"
+)
+
+// revisionRetention is how long revisions of deleted messages are kept, so their history can still be viewed.
+const revisionRetention = 30 * 24 * time.Hour
+
+// revisionCleanupLoop deletes expired revisions of deleted messages once an hour. It never returns.
+func (bot *Bot) revisionCleanupLoop() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		n, err := bot.DB.DeleteExpiredRevisions(time.Now().Add(-revisionRetention))
+		if err != nil {
+			log.Errorf("deleting expired message revisions: %v", err)
+			continue
+		}
+		if n > 0 {
+			log.Debugf("deleted %v expired message revision(s)", n)
+		}
+	}
+}